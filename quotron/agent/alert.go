@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// Alert is one price-move alert from the stream.
+type Alert struct {
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	PercentChange float64   `json:"percent_change"`
+	Timestamp     time.Time `json:"timestamp"`
+
+	messageID   string // stream entry ID, for acking
+	traceparent string // propagated trace context, when the producer traced
+}
+
+// alertFromFields builds an Alert from the flat field/value list a
+// stream entry carries.
+func alertFromFields(id string, fields []interface{}) (Alert, error) {
+	alert := Alert{messageID: id, Timestamp: time.Now().UTC()}
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, _ := fields[i].(string)
+		value, _ := fields[i+1].(string)
+		var err error
+		switch key {
+		case "symbol":
+			alert.Symbol = value
+		case "price":
+			alert.Price, err = strconv.ParseFloat(value, 64)
+		case "percent_change":
+			alert.PercentChange, err = strconv.ParseFloat(value, 64)
+		case "timestamp":
+			alert.Timestamp, err = time.Parse(time.RFC3339, value)
+		case "traceparent":
+			alert.traceparent = value
+		}
+		if err != nil {
+			return alert, fmt.Errorf("field %s=%q: %w", key, value, err)
+		}
+	}
+
+	if alert.Symbol == "" {
+		return alert, fmt.Errorf("alert has no symbol")
+	}
+	return alert, nil
+}
+
+// processAlert asks the LLM for a short analysis of the move and prints
+// it, forwarding to the webhook and email sinks when configured. The
+// prompt is enriched with the latest stored quote when the API service
+// is reachable; when it isn't, analysis degrades to the alert alone.
+func processAlert(ctx context.Context, llm *RateLimitedLLM, api *APIClient, webhook *WebhookNotifier, email *EmailNotifier, alert Alert) {
+	// One malformed alert must not kill the consumer loop: recover, log,
+	// and move on to the next message.
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("ERROR: panic processing alert for %s: %v\n%s", alert.Symbol, v, debug.Stack())
+		}
+	}()
+
+	// Join the producer's trace when the stream message carried one, so
+	// the alert's processing shows up under the request that raised it.
+	ctx, span := trace.StartSpan(trace.WithRemote(ctx, alert.traceparent), "agent.processAlert")
+	span.SetAttr("symbol", alert.Symbol)
+	defer span.End()
+
+	prompt := fmt.Sprintf(
+		"%s moved %.2f%% to $%.2f. In two sentences, give a neutral, factual note on what a retail investor should check before reacting.",
+		alert.Symbol, alert.PercentChange, alert.Price)
+
+	if api != nil {
+		quote, err := api.FetchStockData(ctx, alert.Symbol)
+		switch {
+		case err == nil:
+			volume := "volume unknown"
+			if quote.Volume != nil {
+				volume = fmt.Sprintf("volume %d", *quote.Volume)
+			}
+			prompt = fmt.Sprintf("%s Latest stored quote: $%.2f on %s (%s).",
+				prompt, quote.Price, quote.Exchange, volume)
+		default:
+			var unavailable *APIUnavailableError
+			if errors.As(err, &unavailable) {
+				log.Printf("WARNING: %s; analyzing without market context", unavailable)
+			} else {
+				log.Printf("WARNING: fetching quote for %s: %v", alert.Symbol, err)
+			}
+		}
+	}
+
+	analysis, err := llm.Analyze(ctx, alert.Symbol, prompt)
+	if err == ErrRateLimited {
+		// The move was already logged by the limiter; the next analysis
+		// for this symbol will cover it.
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: LLM analysis for %s failed: %v", alert.Symbol, err)
+		analysis = "(analysis unavailable)"
+	}
+
+	fmt.Printf("[ALERT] %s %+.2f%% @ $%.2f\n%s\n\n", alert.Symbol, alert.PercentChange, alert.Price, analysis)
+
+	if webhook != nil {
+		if err := webhook.Notify(ctx, alert, analysis); err != nil {
+			log.Printf("ERROR: webhook delivery for %s failed: %v", alert.Symbol, err)
+		}
+	}
+	if email != nil {
+		email.Add(alert, analysis)
+	}
+}
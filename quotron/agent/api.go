@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// healthCacheTTL is how long one health probe result is trusted, so a
+// busy session doesn't hit /health before every single fetch.
+const healthCacheTTL = 30 * time.Second
+
+// healthProbeTimeout keeps the pre-check snappy: if /health doesn't
+// answer quickly the service is as good as down for our purposes.
+const healthProbeTimeout = 2 * time.Second
+
+// APIUnavailableError is returned when the Quotron API service cannot
+// be reached, carrying the address so the caller can tell the user
+// where it looked.
+type APIUnavailableError struct {
+	Addr string
+}
+
+func (e *APIUnavailableError) Error() string {
+	return fmt.Sprintf("financial data service is currently unreachable at %s", e.Addr)
+}
+
+// APIClient fetches quote and index data from the Quotron API service,
+// with a cached health pre-check so a down service fails fast and
+// friendly instead of timing out on every fetch.
+type APIClient struct {
+	baseURL string
+	http    *http.Client
+
+	mu          sync.Mutex
+	healthOK    bool
+	lastProbeAt time.Time
+}
+
+// NewAPIClient builds a client for the API service at baseURL.
+func NewAPIClient(baseURL string) *APIClient {
+	return &APIClient{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// healthy runs the cached health pre-check, returning an
+// *APIUnavailableError when the service doesn't answer.
+func (c *APIClient) healthy(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastProbeAt) < healthCacheTTL {
+		if c.healthOK {
+			return nil
+		}
+		return &APIUnavailableError{Addr: c.baseURL}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	c.lastProbeAt = time.Now()
+	c.healthOK = false
+
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return &APIUnavailableError{Addr: c.baseURL}
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &APIUnavailableError{Addr: c.baseURL}
+	}
+
+	c.healthOK = true
+	return nil
+}
+
+// FetchStockData returns the latest quote for a symbol.
+func (c *APIClient) FetchStockData(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	var quote models.StockQuote
+	if err := c.get(ctx, "/api/quote/"+symbol, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// FetchCryptoBatch returns quotes for several crypto pairs in one round
+// trip to /api/crypto/batch. A partial upstream failure is not an
+// error: the quotes that did resolve come back alongside a per-symbol
+// error map for the rest.
+func (c *APIClient) FetchCryptoBatch(ctx context.Context, symbols []string) ([]*models.StockQuote, map[string]string, error) {
+	if err := c.healthy(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	path := "/api/crypto/batch?symbols=" + url.QueryEscape(strings.Join(symbols, ","))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	trace.Inject(ctx, req.Header)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, &APIUnavailableError{Addr: c.baseURL}
+	}
+	defer resp.Body.Close()
+
+	// 207 is the batch endpoint's "some symbols failed" answer; the
+	// body still carries everything that succeeded.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusMultiStatus {
+		return nil, nil, fmt.Errorf("GET /api/crypto/batch: %s", resp.Status)
+	}
+	var body struct {
+		Quotes []*models.StockQuote `json:"quotes"`
+		Errors map[string]string    `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, nil, err
+	}
+	return body.Quotes, body.Errors, nil
+}
+
+// FetchMarketData returns the latest value for a market index. The
+// name may be any alias the shared table knows ("DOW", "Dow Jones",
+// "^DJI").
+func (c *APIClient) FetchMarketData(ctx context.Context, index string) (*models.MarketIndex, error) {
+	symbol, _ := models.ResolveIndex(index)
+	var idx models.MarketIndex
+	if err := c.get(ctx, "/api/index/"+symbol, &idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
+
+// get runs the health pre-check, then fetches and decodes one endpoint.
+func (c *APIClient) get(ctx context.Context, path string, out interface{}) error {
+	if err := c.healthy(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	trace.Inject(ctx, req.Header)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return &APIUnavailableError{Addr: c.baseURL}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchStockDataWhenAPIDown(t *testing.T) {
+	// A closed server: connection refused, like the API service not
+	// running at all.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	api := NewAPIClient(srv.URL)
+	_, err := api.FetchStockData(context.Background(), "AAPL")
+
+	var unavailable *APIUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("err = %v, want *APIUnavailableError", err)
+	}
+	if unavailable.Addr != srv.URL {
+		t.Errorf("Addr = %q, want %q", unavailable.Addr, srv.URL)
+	}
+	if !strings.Contains(unavailable.Error(), "unreachable at "+srv.URL) {
+		t.Errorf("message %q should name the address", unavailable.Error())
+	}
+}
+
+func TestHealthProbeIsCached(t *testing.T) {
+	var healthCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			healthCalls++
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Write([]byte(`{"symbol":"AAPL","price":123.45}`))
+	}))
+	defer srv.Close()
+
+	api := NewAPIClient(srv.URL)
+	for i := 0; i < 3; i++ {
+		if _, err := api.FetchStockData(context.Background(), "AAPL"); err != nil {
+			t.Fatalf("FetchStockData: %v", err)
+		}
+	}
+	if healthCalls != 1 {
+		t.Errorf("health probes = %d, want 1 (cached for %v)", healthCalls, healthCacheTTL)
+	}
+}
+
+func TestUnavailableResultIsCached(t *testing.T) {
+	var healthCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			healthCalls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	api := NewAPIClient(srv.URL)
+	for i := 0; i < 3; i++ {
+		_, err := api.FetchStockData(context.Background(), "AAPL")
+		var unavailable *APIUnavailableError
+		if !errors.As(err, &unavailable) {
+			t.Fatalf("err = %v, want *APIUnavailableError", err)
+		}
+	}
+	if healthCalls != 1 {
+		t.Errorf("health probes = %d, want 1 (failure cached too)", healthCalls)
+	}
+}
+
+func TestFetchCryptoBatchPartialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.WriteHeader(http.StatusOK)
+		case "/api/crypto/batch":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `{"quotes":[{"symbol":"BTC-USD","price":64000}],"errors":{"ETH-USD":"upstream timeout"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewAPIClient(srv.URL)
+	quotes, errs, err := client.FetchCryptoBatch(context.Background(), []string{"BTC", "ETH"})
+	if err != nil {
+		t.Fatalf("FetchCryptoBatch: %v", err)
+	}
+	if len(quotes) != 1 || quotes[0].Symbol != "BTC-USD" {
+		t.Errorf("quotes = %+v, want just BTC-USD", quotes)
+	}
+	if errs["ETH-USD"] != "upstream timeout" {
+		t.Errorf("errs = %v, want the ETH-USD failure", errs)
+	}
+}
+
+func TestFetchCryptoBatchServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := NewAPIClient(srv.URL)
+	if _, _, err := client.FetchCryptoBatch(context.Background(), []string{"BTC"}); err == nil {
+		t.Error("a 500 from the batch endpoint should surface as an error")
+	}
+}
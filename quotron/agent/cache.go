@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedAnalyzer caches replies keyed by a normalized query string so
+// identical questions asked in quick succession ("price of AAPL")
+// reuse one LLM round-trip. The TTL is deliberately short: the cache
+// is for repeated questions, not a substitute for fresh data. Off by
+// default for the alerter, where every alert is distinct anyway.
+type CachedAnalyzer struct {
+	llm Analyzer
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time // swapped in tests
+}
+
+type cacheEntry struct {
+	reply   string
+	expires time.Time
+}
+
+// NewCachedAnalyzer wraps llm with a reply cache; ttl <= 0 disables
+// caching and returns llm unchanged.
+func NewCachedAnalyzer(llm Analyzer, ttl time.Duration) Analyzer {
+	if ttl <= 0 {
+		return llm
+	}
+	return &CachedAnalyzer{
+		llm:     llm,
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// normalizeQuery folds case and whitespace so trivial variations of the
+// same question share a cache slot.
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// Analyze returns the cached reply for an identical recent query, or
+// asks the wrapped backend and remembers the answer.
+func (c *CachedAnalyzer) Analyze(ctx context.Context, prompt string) (string, error) {
+	key := normalizeQuery(prompt)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.now().Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.reply, nil
+	}
+	c.mu.Unlock()
+
+	reply, err := c.llm.Analyze(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.prune()
+	c.entries[key] = cacheEntry{reply: reply, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return reply, nil
+}
+
+// prune drops expired entries. Called with the lock held.
+func (c *CachedAnalyzer) prune() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Usage reports the wrapped backend's cumulative usage; cache hits add
+// nothing, which is the point.
+func (c *CachedAnalyzer) Usage() (promptTokens, completionTokens int, estimatedCostUSD float64) {
+	return c.llm.Usage()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingAnalyzer is a stub backend that counts calls.
+type countingAnalyzer struct {
+	calls int
+	reply string
+}
+
+func (a *countingAnalyzer) Analyze(ctx context.Context, prompt string) (string, error) {
+	a.calls++
+	return a.reply, nil
+}
+
+func (a *countingAnalyzer) Usage() (int, int, float64) { return 0, 0, 0 }
+
+func TestCacheReturnsRecentReply(t *testing.T) {
+	backend := &countingAnalyzer{reply: "cached answer"}
+	cached := NewCachedAnalyzer(backend, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		reply, err := cached.Analyze(context.Background(), "price of AAPL")
+		if err != nil {
+			t.Fatalf("Analyze: %v", err)
+		}
+		if reply != "cached answer" {
+			t.Errorf("reply = %q", reply)
+		}
+	}
+	if backend.calls != 1 {
+		t.Errorf("backend calls = %d, want 1", backend.calls)
+	}
+}
+
+func TestCacheNormalizesQueries(t *testing.T) {
+	backend := &countingAnalyzer{reply: "r"}
+	cached := NewCachedAnalyzer(backend, time.Minute)
+
+	cached.Analyze(context.Background(), "Price of AAPL")
+	cached.Analyze(context.Background(), "  price   of aapl ")
+
+	if backend.calls != 1 {
+		t.Errorf("backend calls = %d, want 1 (queries differ only in case/spacing)", backend.calls)
+	}
+}
+
+func TestCacheExpiresAfterTTL(t *testing.T) {
+	backend := &countingAnalyzer{reply: "r"}
+	cached := NewCachedAnalyzer(backend, time.Minute).(*CachedAnalyzer)
+
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+
+	cached.Analyze(context.Background(), "price of AAPL")
+	now = now.Add(2 * time.Minute)
+	cached.Analyze(context.Background(), "price of AAPL")
+
+	if backend.calls != 2 {
+		t.Errorf("backend calls = %d, want 2 (entry expired)", backend.calls)
+	}
+}
+
+func TestZeroTTLDisablesCaching(t *testing.T) {
+	backend := &countingAnalyzer{reply: "r"}
+	if got := NewCachedAnalyzer(backend, 0); got != Analyzer(backend) {
+		t.Error("ttl 0 should return the backend unchanged")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The agent's configuration can come from a JSON file (--config) so
+// long flag sets for recurring setups don't need re-typing — and
+// credentials stay out of shell history when the file is permissioned.
+// Keys are flag names, values are the flag's string form; precedence is
+// command line > file > built-in default.
+
+// applyConfigFile layers file values under the command line: every
+// flag the user did not pass explicitly takes its value from the file.
+// Unknown keys are errors, so a typo fails loudly instead of being
+// silently ignored.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for name, value := range values {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown option %q", path, name)
+		}
+		if set[name] || name == "config" {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("%s: option %q: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// writeDefaultConfig emits a config file holding every flag's built-in
+// default, the starting point `agent gen-config > agent.json` gives an
+// operator to edit down.
+func writeDefaultConfig(w io.Writer, fs *flag.FlagSet) error {
+	cfg := map[string]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		if _, deprecated := deprecatedFlagHints[f.Name]; deprecated {
+			return
+		}
+		cfg[f.Name] = f.DefValue
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFlagSet builds a small stand-in for the real command line.
+func testFlagSet() (*flag.FlagSet, *string, *int) {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	model := fs.String("model", "gpt-4o-mini", "")
+	rate := fs.Int("llm-rate-limit", 10, "")
+	fs.String("config", "", "")
+	return fs, model, rate
+}
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFileFillsUnsetFlags(t *testing.T) {
+	fs, model, rate := testFlagSet()
+	fs.Parse(nil)
+
+	path := writeConfig(t, `{"model": "llama3", "llm-rate-limit": "5"}`)
+	if err := applyConfigFile(fs, path); err != nil {
+		t.Fatal(err)
+	}
+	if *model != "llama3" || *rate != 5 {
+		t.Errorf("model = %q, rate = %d; want the file's llama3 and 5", *model, *rate)
+	}
+}
+
+func TestCommandLineOverridesConfigFile(t *testing.T) {
+	fs, model, rate := testFlagSet()
+	fs.Parse([]string{"-model", "gpt-4o"})
+
+	path := writeConfig(t, `{"model": "llama3", "llm-rate-limit": "5"}`)
+	if err := applyConfigFile(fs, path); err != nil {
+		t.Fatal(err)
+	}
+	if *model != "gpt-4o" {
+		t.Errorf("model = %q, the explicit flag should win over the file", *model)
+	}
+	if *rate != 5 {
+		t.Errorf("rate = %d, unset flags should still take the file's value", *rate)
+	}
+}
+
+func TestConfigFileRejectsUnknownKeys(t *testing.T) {
+	fs, _, _ := testFlagSet()
+	fs.Parse(nil)
+
+	path := writeConfig(t, `{"modle": "typo"}`)
+	err := applyConfigFile(fs, path)
+	if err == nil || !strings.Contains(err.Error(), `unknown option "modle"`) {
+		t.Errorf("err = %v, want an unknown-option error naming the typo", err)
+	}
+}
+
+func TestConfigFileRejectsBadValues(t *testing.T) {
+	fs, _, _ := testFlagSet()
+	fs.Parse(nil)
+
+	path := writeConfig(t, `{"llm-rate-limit": "plenty"}`)
+	if err := applyConfigFile(fs, path); err == nil {
+		t.Error("want an error for a non-numeric rate limit")
+	}
+}
+
+func TestGeneratedConfigRoundTrips(t *testing.T) {
+	fs, model, _ := testFlagSet()
+	var buf bytes.Buffer
+	if err := writeDefaultConfig(&buf, fs); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `"config"`) {
+		t.Error("generated config should not contain the config flag itself")
+	}
+
+	path := writeConfig(t, buf.String())
+	fs.Parse(nil)
+	if err := applyConfigFile(fs, path); err != nil {
+		t.Fatalf("generated config should apply cleanly: %v", err)
+	}
+	if *model != "gpt-4o-mini" {
+		t.Errorf("model = %q, want the default back", *model)
+	}
+}
+
+func TestGeneratedConfigOmitsDeprecatedFlags(t *testing.T) {
+	fs, _, _ := testFlagSet()
+	registerDeprecatedFlags(fs)
+	var buf bytes.Buffer
+	if err := writeDefaultConfig(&buf, fs); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "use-real-api") {
+		t.Error("generated config should not resurrect deprecated flags")
+	}
+}
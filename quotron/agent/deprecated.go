@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+)
+
+// deprecatedFlagHints maps legacy flags to the explanation printed when
+// they are used. The flags stay registered so old wrapper scripts keep
+// parsing, but they have no effect: market data always comes from the
+// API service now (see -api-url), never from a direct finance API call.
+var deprecatedFlagHints = map[string]string{
+	"use-real-api":    "market data always comes from the API service; point -api-url at it instead",
+	"finance-api-key": "the agent no longer calls the finance API directly; drop the key",
+}
+
+// registerDeprecatedFlags keeps the legacy flags parseable as no-ops.
+func registerDeprecatedFlags(fs *flag.FlagSet) {
+	fs.Bool("use-real-api", false, "deprecated, ignored: "+deprecatedFlagHints["use-real-api"])
+	fs.String("finance-api-key", "", "deprecated, ignored: "+deprecatedFlagHints["finance-api-key"])
+}
+
+// warnDeprecatedFlags logs a warning for each legacy flag explicitly set
+// on the command line, so operators notice before the flags are removed.
+// Returns how many warnings fired, for tests.
+func warnDeprecatedFlags(fs *flag.FlagSet) int {
+	warned := 0
+	fs.Visit(func(f *flag.Flag) {
+		if hint, ok := deprecatedFlagHints[f.Name]; ok {
+			log.Printf("WARNING: -%s is deprecated and ignored: %s (the flag will be removed in a future release)", f.Name, hint)
+			warned++
+		}
+	})
+	return warned
+}
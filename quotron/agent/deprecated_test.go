@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"strings"
+	"testing"
+)
+
+func parseWithDeprecated(t *testing.T, args ...string) (*flag.FlagSet, *bytes.Buffer) {
+	t.Helper()
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	registerDeprecatedFlags(fs)
+
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("parsing %v: %v", args, err)
+	}
+	return fs, &buf
+}
+
+func TestDeprecatedFlagWarns(t *testing.T) {
+	fs, buf := parseWithDeprecated(t, "-use-real-api", "-finance-api-key", "sk-123")
+
+	if got := warnDeprecatedFlags(fs); got != 2 {
+		t.Errorf("warnings = %d, want 2", got)
+	}
+	out := buf.String()
+	for _, want := range []string{"-use-real-api is deprecated", "-finance-api-key is deprecated", "removed in a future release"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("warning output %q missing %q", out, want)
+		}
+	}
+}
+
+func TestNoWarningWithoutDeprecatedFlags(t *testing.T) {
+	fs, buf := parseWithDeprecated(t)
+
+	if got := warnDeprecatedFlags(fs); got != 0 {
+		t.Errorf("warnings = %d, want 0", got)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto/tls"
+)
+
+// digestWindow is how long alerts are batched before one digest email
+// goes out, so a volatile minute doesn't produce a mailbox flood.
+const digestWindow = 2 * time.Minute
+
+// SMTPConfig carries the --smtp-* flag values.
+type SMTPConfig struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	To   string
+}
+
+// complete reports whether enough config is present to send mail.
+// User/pass are optional (open relays on dev setups); host and
+// recipient are not.
+func (c SMTPConfig) complete() bool {
+	return c.Host != "" && c.Port != 0 && c.To != ""
+}
+
+// emailAlert is one processed alert waiting for the next digest.
+type emailAlert struct {
+	alert    Alert
+	analysis string
+}
+
+// EmailNotifier batches processed alerts and sends them as digest
+// emails over SMTP with STARTTLS.
+type EmailNotifier struct {
+	cfg SMTPConfig
+
+	mu      sync.Mutex
+	pending []emailAlert
+	timer   *time.Timer
+}
+
+// NewEmailNotifier builds a notifier, or returns nil (logging a single
+// warning) when the SMTP configuration is incomplete so callers can
+// treat email as disabled.
+func NewEmailNotifier(cfg SMTPConfig) *EmailNotifier {
+	if !cfg.complete() {
+		if cfg.Host != "" || cfg.To != "" {
+			log.Printf("WARNING: incomplete SMTP config (need --smtp-host, --smtp-port, --alert-to); email alerts disabled")
+		}
+		return nil
+	}
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Add queues one alert for the next digest, arming the flush timer on
+// the first alert of a window.
+func (n *EmailNotifier) Add(alert Alert, analysis string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pending = append(n.pending, emailAlert{alert: alert, analysis: analysis})
+	if n.timer == nil {
+		n.timer = time.AfterFunc(digestWindow, n.flush)
+	}
+}
+
+// flush sends everything queued in the window as one digest.
+func (n *EmailNotifier) flush() {
+	n.mu.Lock()
+	batch := n.pending
+	n.pending = nil
+	n.timer = nil
+	n.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+	if err := n.sendDigest(batch); err != nil {
+		log.Printf("ERROR: sending alert digest (%d alerts): %v", len(batch), err)
+	}
+}
+
+// Flush sends any pending digest immediately (used on shutdown).
+func (n *EmailNotifier) Flush() {
+	n.mu.Lock()
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	n.mu.Unlock()
+	n.flush()
+}
+
+// sendDigest formats and delivers one digest email via STARTTLS.
+func (n *EmailNotifier) sendDigest(batch []emailAlert) error {
+	subject := fmt.Sprintf("Quotron: %d price alert(s)", len(batch))
+	body := formatDigest(batch)
+
+	from := n.cfg.User
+	if from == "" {
+		from = "quotron@" + n.cfg.Host
+	}
+
+	msg := strings.Join([]string{
+		"From: " + from,
+		"To: " + n.cfg.To,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := net.JoinHostPort(n.cfg.Host, fmt.Sprint(n.cfg.Port))
+	client, err := smtp.Dial(addr)
+	if err != nil {
+		return fmt.Errorf("dialing SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	if n.cfg.User != "" {
+		auth := smtp.PlainAuth("", n.cfg.User, n.cfg.Pass, n.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(n.cfg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// formatDigest renders the plain-text digest body.
+func formatDigest(batch []emailAlert) string {
+	var b strings.Builder
+	for _, item := range batch {
+		fmt.Fprintf(&b, "%s %+.2f%% @ $%.2f (%s)\n",
+			item.alert.Symbol, item.alert.PercentChange, item.alert.Price,
+			item.alert.Timestamp.Format(time.RFC3339))
+		if item.analysis != "" {
+			fmt.Fprintf(&b, "  %s\n", item.analysis)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// openAIBaseURL is the chat-completions endpoint the agent talks to.
+const openAIBaseURL = "https://api.openai.com/v1"
+
+// llmMaxAttempts is how many times one request is tried end to end.
+const llmMaxAttempts = 4
+
+// LLMClient is a minimal OpenAI chat-completions client with retry on
+// transient failures.
+type LLMClient struct {
+	apiKey  string
+	model   string
+	baseURL string
+	http    *http.Client
+	// backoff is the initial retry delay, doubled per attempt. A field
+	// so tests don't have to sleep.
+	backoff time.Duration
+	usage   usageTracker
+}
+
+// NewLLMClient builds a client for the given model.
+func NewLLMClient(apiKey, model string) *LLMClient {
+	return &LLMClient{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: openAIBaseURL,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		backoff: time.Second,
+	}
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// retryableStatus reports whether a status code is worth retrying:
+// rate limits and transient server errors, but never client mistakes
+// like 400/401.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= 500 && status <= 504)
+}
+
+// retryDelay picks the wait before the next attempt, honoring a
+// Retry-After header (seconds) when the server sent one.
+func retryDelay(resp *http.Response, backoff time.Duration) time.Duration {
+	if resp != nil {
+		if after := resp.Header.Get("Retry-After"); after != "" {
+			if secs, err := time.ParseDuration(after + "s"); err == nil {
+				return secs
+			}
+		}
+	}
+	return backoff
+}
+
+// Analyze sends one prompt and returns the model's reply text, retrying
+// 429s, transient 5xx, and network errors with exponential backoff.
+func (c *LLMClient) Analyze(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You are a concise financial data assistant. Never give investment advice."},
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	backoff := c.backoff
+	var lastErr error
+	for attempt := 1; attempt <= llmMaxAttempts; attempt++ {
+		reply, retryAfter, err := c.doChatRequest(ctx, body)
+		if err == nil {
+			return reply, nil
+		}
+		if perm, ok := err.(*llmPermanentError); ok {
+			return "", perm
+		}
+		lastErr = err
+
+		if attempt < llmMaxAttempts {
+			delay := backoff
+			if retryAfter > 0 {
+				delay = retryAfter
+			}
+			select {
+			case <-time.After(delay):
+				backoff *= 2
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	}
+	return "", fmt.Errorf("after %d attempts: %w", llmMaxAttempts, lastErr)
+}
+
+// llmPermanentError is a failure retrying cannot fix (4xx except 429).
+type llmPermanentError struct{ msg string }
+
+func (e *llmPermanentError) Error() string { return e.msg }
+
+// doChatRequest performs one HTTP attempt. retryAfter is non-zero when
+// the server asked for a specific wait.
+func (c *LLMClient) doChatRequest(ctx context.Context, body []byte) (reply string, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", 0, err // network errors are retryable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if retryableStatus(resp.StatusCode) {
+			return "", retryDelay(resp, 0), fmt.Errorf("LLM API returned %s: %s", resp.Status, msg)
+		}
+		return "", 0, &llmPermanentError{
+			msg: fmt.Sprintf("LLM API rejected the request (%s): %s", resp.Status, msg),
+		}
+	}
+
+	var parsed chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", 0, fmt.Errorf("decoding LLM response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", 0, fmt.Errorf("LLM response has no choices")
+	}
+	c.usage.add(parsed.Usage.PromptTokens, parsed.Usage.CompletionTokens)
+	return parsed.Choices[0].Message.Content, 0, nil
+}
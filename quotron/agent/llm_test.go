@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const chatOK = `{"choices":[{"message":{"role":"assistant","content":"looks fine"}}]}`
+
+// testLLMClient points a client at a local mock server with no backoff
+// sleeps.
+func testLLMClient(url string) *LLMClient {
+	c := NewLLMClient("test-key", "test-model")
+	c.baseURL = url
+	c.backoff = time.Millisecond
+	return c
+}
+
+func TestAnalyzeRetriesAfter429(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(chatOK))
+	}))
+	defer srv.Close()
+
+	reply, err := testLLMClient(srv.URL).Analyze(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if reply != "looks fine" {
+		t.Errorf("reply = %q", reply)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (two 429s then success)", calls)
+	}
+}
+
+func TestAnalyzeDoesNotRetryAuthErrors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"error":"bad key"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := testLLMClient(srv.URL).Analyze(context.Background(), "prompt")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (401 must not be retried)", calls)
+	}
+	if !strings.Contains(err.Error(), "rejected the request") {
+		t.Errorf("error %q should name the rejection", err)
+	}
+}
+
+func TestAnalyzeGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	_, err := testLLMClient(srv.URL).Analyze(context.Background(), "prompt")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != llmMaxAttempts {
+		t.Errorf("calls = %d, want %d", calls, llmMaxAttempts)
+	}
+}
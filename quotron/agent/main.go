@@ -0,0 +1,124 @@
+// agent is the AI alerter: it consumes price-move alerts from the Redis
+// stream, asks the LLM for a short analysis, and prints it (optionally
+// forwarding to a webhook).
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+func main() {
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	stream := flag.String("stream", events.AlertStream, "alert stream to consume")
+	group := flag.String("group", events.AlertGroup, "consumer group name")
+	consumer := flag.String("consumer", "agent-1", "consumer name within the group")
+	streamMaxLen := flag.Int("stream-maxlen", 0, "trim the alert stream to about this many entries at startup (0 = no trim)")
+	claimIdle := flag.Duration("claim-idle", time.Minute, "reclaim alerts another consumer left unacked for this long (0 = disabled)")
+	claimCount := flag.Int("claim-count", 10, "max pending alerts reclaimed per scan")
+	model := flag.String("model", "gpt-4o-mini", "LLM model for analysis")
+	llmProvider := flag.String("llm-provider", "openai", "LLM provider: openai or ollama")
+	baseURL := flag.String("base-url", "", "override the provider's API base URL (e.g. http://localhost:11434 for ollama)")
+	llmRateLimit := flag.Int("llm-rate-limit", 10, "max LLM calls per minute (0 = unlimited)")
+	chatCacheTTL := flag.Duration("chat-cache-ttl", 0, "cache replies to identical queries for this long (0 = disabled)")
+	apiURL := flag.String("api-url", "http://localhost:8080", "Quotron API service base URL (empty = no market context)")
+	webhookURL := flag.String("webhook-url", "", "POST processed alerts to this URL (unset = disabled)")
+	smtpHost := flag.String("smtp-host", "", "SMTP host for email alerts (unset = disabled)")
+	smtpPort := flag.Int("smtp-port", 587, "SMTP port")
+	smtpUser := flag.String("smtp-user", "", "SMTP username (optional)")
+	smtpPass := flag.String("smtp-pass", "", "SMTP password (optional)")
+	alertTo := flag.String("alert-to", "", "recipient address for alert digest emails")
+	traceEndpoint := flag.String("trace-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "collector URL for trace spans (empty = no export)")
+	configPath := flag.String("config", "", "JSON file of flag defaults (command-line flags override; see gen-config)")
+	registerDeprecatedFlags(flag.CommandLine)
+	flag.Parse()
+	warnDeprecatedFlags(flag.CommandLine)
+
+	if flag.Arg(0) == "gen-config" {
+		if err := writeDefaultConfig(os.Stdout, flag.CommandLine); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *configPath != "" {
+		if err := applyConfigFile(flag.CommandLine, *configPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *traceEndpoint != "" {
+		trace.SetExporter(trace.NewHTTPExporter(*traceEndpoint))
+		log.Printf("exporting trace spans to %s", *traceEndpoint)
+	}
+
+	backend, err := newAnalyzer(*llmProvider, *baseURL, *model)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	llm := NewRateLimitedLLM(NewCachedAnalyzer(backend, *chatCacheTTL), *llmRateLimit)
+	defer func() {
+		prompt, completion, cost := llm.Usage()
+		log.Print(formatUsage(prompt, completion, cost))
+	}()
+
+	var api *APIClient
+	if *apiURL != "" {
+		api = NewAPIClient(*apiURL)
+	}
+
+	var webhook *WebhookNotifier
+	if *webhookURL != "" {
+		webhook = NewWebhookNotifier(*webhookURL)
+		log.Printf("forwarding alerts to webhook %s", *webhookURL)
+	}
+
+	email := NewEmailNotifier(SMTPConfig{
+		Host: *smtpHost,
+		Port: *smtpPort,
+		User: *smtpUser,
+		Pass: *smtpPass,
+		To:   *alertTo,
+	})
+	if email != nil {
+		defer email.Flush()
+		log.Printf("emailing alert digests to %s", *alertTo)
+	}
+
+	queue, err := NewQueueConsumer(*redisAddr, *stream, *group, *consumer, *streamMaxLen)
+	if err != nil {
+		log.Fatalf("connecting to Redis: %v", err)
+	}
+	defer queue.Close()
+	queue.SetClaimPolicy(*claimIdle, *claimCount)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("consuming alerts from %s (group %s)", *stream, *group)
+	for ctx.Err() == nil {
+		alerts, err := queue.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("ERROR: reading alerts: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, alert := range alerts {
+			processAlert(ctx, llm, api, webhook, email, alert)
+			if err := queue.Ack(ctx, alert.messageID); err != nil {
+				log.Printf("WARNING: acking %s: %v", alert.messageID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ollamaDefaultBaseURL is where a stock local Ollama install listens.
+const ollamaDefaultBaseURL = "http://localhost:11434"
+
+// OllamaClient talks to a local Ollama server's /api/chat endpoint.
+// No API key is involved and token usage costs nothing, but usage is
+// still tracked so the shutdown summary stays meaningful.
+type OllamaClient struct {
+	model   string
+	baseURL string
+	http    *http.Client
+	backoff time.Duration
+	usage   usageTracker
+}
+
+// NewOllamaClient builds a client for a local model. An empty baseURL
+// means the default Ollama address.
+func NewOllamaClient(baseURL, model string) *OllamaClient {
+	if baseURL == "" {
+		baseURL = ollamaDefaultBaseURL
+	}
+	return &OllamaClient{
+		model:   model,
+		baseURL: baseURL,
+		// Local models can be slow on first load (cold weights).
+		http:    &http.Client{Timeout: 120 * time.Second},
+		backoff: time.Second,
+	}
+}
+
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	// Stream false so we get one JSON object back instead of NDJSON
+	// chunks; the agent has no use for partial replies.
+	Stream bool `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message chatMessage `json:"message"`
+	// Ollama's token counters, its equivalent of OpenAI's usage block.
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// Analyze sends one prompt to the local model and returns its reply,
+// retrying transient failures the same way the OpenAI client does.
+func (c *OllamaClient) Analyze(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(ollamaChatRequest{
+		Model: c.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You are a concise financial data assistant. Never give investment advice."},
+			{Role: "user", Content: prompt},
+		},
+		Stream: false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	backoff := c.backoff
+	var lastErr error
+	for attempt := 1; attempt <= llmMaxAttempts; attempt++ {
+		reply, err := c.doChatRequest(ctx, body)
+		if err == nil {
+			return reply, nil
+		}
+		if perm, ok := err.(*llmPermanentError); ok {
+			return "", perm
+		}
+		lastErr = err
+
+		if attempt < llmMaxAttempts {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	}
+	return "", fmt.Errorf("after %d attempts: %w", llmMaxAttempts, lastErr)
+}
+
+// doChatRequest performs one HTTP attempt against /api/chat.
+func (c *OllamaClient) doChatRequest(ctx context.Context, body []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err // network errors are retryable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if retryableStatus(resp.StatusCode) {
+			return "", fmt.Errorf("Ollama returned %s: %s", resp.Status, msg)
+		}
+		return "", &llmPermanentError{
+			msg: fmt.Sprintf("Ollama rejected the request (%s): %s", resp.Status, msg),
+		}
+	}
+
+	var parsed ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("decoding Ollama response: %w", err)
+	}
+	if parsed.Message.Content == "" {
+		return "", fmt.Errorf("Ollama response has no message content")
+	}
+	c.usage.add(parsed.PromptEvalCount, parsed.EvalCount)
+	return parsed.Message.Content, nil
+}
+
+// Usage returns cumulative token counts. Local inference is free, so
+// the estimated cost is always zero.
+func (c *OllamaClient) Usage() (promptTokens, completionTokens int, estimatedCostUSD float64) {
+	promptTokens, completionTokens = c.usage.totals()
+	return promptTokens, completionTokens, 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const ollamaChatOK = `{
+	"message":{"role":"assistant","content":"local reply"},
+	"prompt_eval_count":42,
+	"eval_count":7
+}`
+
+func TestOllamaAnalyze(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s, want /api/chat", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+		w.Write([]byte(ollamaChatOK))
+	}))
+	defer srv.Close()
+
+	c := NewOllamaClient(srv.URL, "llama3")
+	c.backoff = time.Millisecond
+
+	reply, err := c.Analyze(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if reply != "local reply" {
+		t.Errorf("reply = %q", reply)
+	}
+
+	prompt, completion, cost := c.Usage()
+	if prompt != 42 || completion != 7 {
+		t.Errorf("tokens = %d/%d, want 42/7", prompt, completion)
+	}
+	if cost != 0 {
+		t.Errorf("cost = %v, want 0 (local inference is free)", cost)
+	}
+}
+
+func TestNewAnalyzerRejectsUnknownProvider(t *testing.T) {
+	if _, err := newAnalyzer("bard", "", "m"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestNewAnalyzerOllamaNeedsNoKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := newAnalyzer("ollama", "", "llama3"); err != nil {
+		t.Errorf("ollama provider should not need an API key: %v", err)
+	}
+}
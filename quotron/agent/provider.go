@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// newAnalyzer picks the LLM backend for the configured provider.
+//
+// "openai" requires OPENAI_API_KEY and accepts an optional base URL
+// override (useful for proxies). "ollama" targets a local server and
+// needs no key at all.
+func newAnalyzer(provider, baseURL, model string) (Analyzer, error) {
+	switch provider {
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required for the openai provider")
+		}
+		c := NewLLMClient(apiKey, model)
+		if baseURL != "" {
+			c.baseURL = baseURL
+		}
+		return c, nil
+	case "ollama":
+		return NewOllamaClient(baseURL, model), nil
+	}
+	return nil, fmt.Errorf("unknown LLM provider %q (want openai or ollama)", provider)
+}
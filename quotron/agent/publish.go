@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/redis"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// alertFields flattens an alert into the stream envelope's field/value
+// list — the inverse of alertFromFields. When ctx carries a trace, a
+// traceparent field rides along so the consumer continues the producer's
+// trace instead of starting a fresh one.
+func alertFields(ctx context.Context, alert Alert) []string {
+	fields := []string{
+		"symbol", alert.Symbol,
+		"price", strconv.FormatFloat(alert.Price, 'f', -1, 64),
+		"percent_change", strconv.FormatFloat(alert.PercentChange, 'f', -1, 64),
+		"timestamp", alert.Timestamp.Format(time.RFC3339),
+	}
+	if tp := trace.Traceparent(ctx); tp != "" {
+		fields = append(fields, "traceparent", tp)
+	}
+	return fields
+}
+
+// publishAlert appends an alert to the stream and returns the entry ID.
+// Consumers are the agent itself and, eventually, anything else tailing
+// the stream.
+func publishAlert(ctx context.Context, conn *redis.Conn, stream string, alert Alert) (string, error) {
+	args := append([]string{"XADD", stream, "*"}, alertFields(ctx, alert)...)
+	reply, err := conn.Do(args...)
+	if err != nil {
+		return "", err
+	}
+	id, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected XADD reply %T", reply)
+	}
+	return id, nil
+}
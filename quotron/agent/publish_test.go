@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// roundTrip pushes an alert through the stream envelope: flatten with
+// alertFields, parse back with alertFromFields.
+func roundTrip(t *testing.T, ctx context.Context, alert Alert) Alert {
+	t.Helper()
+	fields := alertFields(ctx, alert)
+	raw := make([]interface{}, len(fields))
+	for i, f := range fields {
+		raw[i] = f
+	}
+	got, err := alertFromFields("1-1", raw)
+	if err != nil {
+		t.Fatalf("alertFromFields: %v", err)
+	}
+	return got
+}
+
+func TestTraceIDSurvivesEnvelopeRoundTrip(t *testing.T) {
+	ctx, span := trace.StartSpan(context.Background(), "scheduler.checkMove")
+	defer span.End()
+
+	alert := Alert{Symbol: "AAPL", Price: 190.5, PercentChange: -3.2, Timestamp: time.Now().UTC().Truncate(time.Second)}
+	got := roundTrip(t, ctx, alert)
+
+	if got.traceparent == "" {
+		t.Fatal("envelope lost the traceparent field")
+	}
+	_, resumed := trace.StartSpan(trace.WithRemote(context.Background(), got.traceparent), "agent.processAlert")
+	if resumed.TraceID != span.TraceID {
+		t.Errorf("consumer trace = %s, want producer's %s", resumed.TraceID, span.TraceID)
+	}
+	if resumed.ParentID != span.SpanID {
+		t.Errorf("consumer parent span = %s, want producer's %s", resumed.ParentID, span.SpanID)
+	}
+}
+
+func TestEnvelopeRoundTripPreservesAlert(t *testing.T) {
+	alert := Alert{Symbol: "MSFT", Price: 410, PercentChange: 2.75, Timestamp: time.Now().UTC().Truncate(time.Second)}
+	got := roundTrip(t, context.Background(), alert)
+
+	if got.Symbol != alert.Symbol || got.Price != alert.Price ||
+		got.PercentChange != alert.PercentChange || !got.Timestamp.Equal(alert.Timestamp) {
+		t.Errorf("round trip changed the alert: %+v != %+v", got, alert)
+	}
+}
+
+func TestUntracedPublishOmitsTraceparent(t *testing.T) {
+	fields := alertFields(context.Background(), Alert{Symbol: "AAPL"})
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "traceparent" {
+			t.Errorf("untraced publish should not carry a traceparent field, got %q", fields[i+1])
+		}
+	}
+}
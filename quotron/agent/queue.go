@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/redis"
+)
+
+// blockMillis is how long one XREADGROUP call blocks waiting for alerts.
+const blockMillis = 5000
+
+// streamSetupTimeout bounds the one-off startup commands (group create,
+// trim) so a wedged server fails fast instead of hanging the boot.
+const streamSetupTimeout = 5 * time.Second
+
+// ensureConsumerGroup idempotently creates the stream and its consumer
+// group. Several consumers starting together all race to create them:
+// MKSTREAM makes stream-plus-group creation atomic on the server, and
+// the losers of the group race get BUSYGROUP, which counts as success.
+// A positive maxLen also trims the stream to roughly that many entries,
+// so a stream nobody consumes cannot grow without bound.
+func ensureConsumerGroup(ctx context.Context, conn *redis.Conn, stream, group string, maxLen int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	conn.SetReadDeadline(time.Now().Add(streamSetupTimeout))
+	if _, err := conn.Do("XGROUP", "CREATE", stream, group, "$", "MKSTREAM"); err != nil {
+		if !strings.Contains(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("creating consumer group %s on %s: %w", group, stream, err)
+		}
+	}
+	if maxLen > 0 {
+		conn.SetReadDeadline(time.Now().Add(streamSetupTimeout))
+		if _, err := conn.Do("XTRIM", stream, "MAXLEN", "~", strconv.Itoa(maxLen)); err != nil {
+			return fmt.Errorf("trimming %s: %w", stream, err)
+		}
+	}
+	return nil
+}
+
+// defaultClaimCount caps how many pending messages one XAUTOCLAIM scan
+// reclaims when no batch size was configured.
+const defaultClaimCount = 10
+
+// Reconnect tuning: the first redial waits reconnectBase, doubling up
+// to reconnectMax, and the consumer gives up after maxReconnectAttempts
+// consecutive failures so a permanently gone server surfaces as an
+// error instead of a silent infinite retry.
+const (
+	reconnectBase        = time.Second
+	reconnectMax         = 30 * time.Second
+	maxReconnectAttempts = 10
+)
+
+// QueueConsumer reads alerts from the Redis stream as part of a consumer
+// group, so multiple agents can share the work.
+type QueueConsumer struct {
+	conn     *redis.Conn
+	addr     string
+	stream   string
+	group    string
+	consumer string
+	maxLen   int
+
+	// Reconnect tuning, defaulted from the package constants; fields so
+	// tests can shrink the delays.
+	reconnectDelay  time.Duration
+	reconnectBudget int
+
+	// Pending-message recovery (see SetClaimPolicy): messages a dead
+	// consumer left unacked are reclaimed once idle past claimIdle.
+	claimIdle  time.Duration
+	claimCount int
+	lastClaim  time.Time
+}
+
+// NewQueueConsumer connects to Redis and ensures the consumer group
+// exists (creating the stream if needed). A positive maxLen caps the
+// stream length at startup; see ensureConsumerGroup.
+func NewQueueConsumer(addr, stream, group, consumer string, maxLen int) (*QueueConsumer, error) {
+	conn, err := redis.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureConsumerGroup(context.Background(), conn, stream, group, maxLen); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &QueueConsumer{
+		conn:            conn,
+		addr:            addr,
+		stream:          stream,
+		group:           group,
+		consumer:        consumer,
+		maxLen:          maxLen,
+		reconnectDelay:  reconnectBase,
+		reconnectBudget: maxReconnectAttempts,
+	}, nil
+}
+
+// Close releases the Redis connection.
+func (q *QueueConsumer) Close() error { return q.conn.Close() }
+
+// SetClaimPolicy enables pending-message recovery: alerts another
+// consumer took but never acked (it crashed mid-processing) are
+// reclaimed once idle longer than idle, up to count per scan, and
+// redelivered through Next. A zero idle disables recovery; count <= 0
+// means the default batch size.
+func (q *QueueConsumer) SetClaimPolicy(idle time.Duration, count int) {
+	q.claimIdle = idle
+	q.claimCount = count
+}
+
+// Next blocks for up to blockMillis and returns any new alerts.
+// Reclaimed pending alerts, when recovery is on, are delivered first.
+func (q *QueueConsumer) Next(ctx context.Context) ([]Alert, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reclaimed, err := q.claimPending()
+	if err != nil {
+		// Recovery is best-effort: an old server without XAUTOCLAIM (or
+		// a transient error) must not stop normal consumption.
+		log.Printf("WARNING: reclaiming pending alerts: %v", err)
+	} else if len(reclaimed) > 0 {
+		log.Printf("reclaimed %d pending alert(s) idle longer than %s", len(reclaimed), q.claimIdle)
+		return reclaimed, nil
+	}
+
+	reply, err := q.readGroup()
+	if isConnError(err) {
+		// The transport died — typically a Redis restart. Redial with
+		// backoff and retry the read once on the fresh connection.
+		log.Printf("WARNING: redis connection lost: %v", err)
+		if err = q.reconnect(ctx); err != nil {
+			return nil, err
+		}
+		reply, err = q.readGroup()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamReply(reply)
+}
+
+// readGroup issues one blocking XREADGROUP call, bounding the socket
+// read a little past the server-side block time.
+func (q *QueueConsumer) readGroup() (interface{}, error) {
+	q.conn.SetReadDeadline(time.Now().Add(blockMillis*time.Millisecond + 2*time.Second))
+	return q.conn.Do("XREADGROUP", "GROUP", q.group, q.consumer,
+		"BLOCK", strconv.Itoa(blockMillis), "COUNT", "10",
+		"STREAMS", q.stream, ">")
+}
+
+// isConnError reports whether err is the transport failing (dropped
+// connection, dead socket, deadline on an unresponsive server) rather
+// than the server answering with an error reply.
+func isConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) || errors.As(err, &opErr)
+}
+
+// reconnect redials Redis with exponential backoff and re-runs the
+// group setup on the fresh connection. It stops early when ctx ends and
+// gives up once the retry budget is spent.
+func (q *QueueConsumer) reconnect(ctx context.Context) error {
+	q.conn.Close()
+
+	delay := q.reconnectDelay
+	for attempt := 1; attempt <= q.reconnectBudget; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		conn, err := redis.Dial(q.addr)
+		if err == nil {
+			if err = ensureConsumerGroup(ctx, conn, q.stream, q.group, q.maxLen); err == nil {
+				q.conn = conn
+				log.Printf("reconnected to Redis at %s (attempt %d)", q.addr, attempt)
+				return nil
+			}
+			conn.Close()
+		}
+		log.Printf("WARNING: reconnect attempt %d/%d failed: %v", attempt, q.reconnectBudget, err)
+		if delay *= 2; delay > reconnectMax {
+			delay = reconnectMax
+		}
+	}
+	return fmt.Errorf("redis unreachable after %d reconnect attempts", q.reconnectBudget)
+}
+
+// claimPending runs one XAUTOCLAIM scan when recovery is enabled and
+// the previous scan was at least claimIdle ago. Scanning on that cadence
+// keeps the extra round trips negligible while bounding how long a
+// stranded alert waits to roughly twice the idle threshold.
+func (q *QueueConsumer) claimPending() ([]Alert, error) {
+	if q.claimIdle <= 0 || time.Since(q.lastClaim) < q.claimIdle {
+		return nil, nil
+	}
+	q.lastClaim = time.Now()
+
+	count := q.claimCount
+	if count <= 0 {
+		count = defaultClaimCount
+	}
+	q.conn.SetReadDeadline(time.Now().Add(streamSetupTimeout))
+	reply, err := q.conn.Do("XAUTOCLAIM", q.stream, q.group, q.consumer,
+		strconv.FormatInt(q.claimIdle.Milliseconds(), 10), "0-0",
+		"COUNT", strconv.Itoa(count))
+	if err != nil {
+		return nil, err
+	}
+	return parseAutoclaimReply(reply)
+}
+
+// Ack confirms an alert was processed.
+func (q *QueueConsumer) Ack(ctx context.Context, messageID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	q.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err := q.conn.Do("XACK", q.stream, q.group, messageID)
+	return err
+}
+
+// parseStreamReply unpacks the nested XREADGROUP reply
+// ([[stream, [[id, [field, value, ...]], ...]]]) into Alerts.
+func parseStreamReply(reply interface{}) ([]Alert, error) {
+	streams, ok := reply.([]interface{})
+	if !ok {
+		return nil, nil // nil reply: block timed out with no messages
+	}
+
+	var alerts []Alert
+	for _, s := range streams {
+		entry, ok := s.([]interface{})
+		if !ok || len(entry) != 2 {
+			continue
+		}
+		messages, ok := entry[1].([]interface{})
+		if !ok {
+			continue
+		}
+		parsed, err := parseMessages(messages)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, parsed...)
+	}
+	return alerts, nil
+}
+
+// parseAutoclaimReply unpacks the XAUTOCLAIM reply
+// ([next-cursor, [[id, [field, value, ...]], ...]]) into Alerts. The
+// trailing deleted-ID list Redis 7 appends is ignored.
+func parseAutoclaimReply(reply interface{}) ([]Alert, error) {
+	parts, ok := reply.([]interface{})
+	if !ok || len(parts) < 2 {
+		return nil, fmt.Errorf("unexpected XAUTOCLAIM reply %T", reply)
+	}
+	messages, _ := parts[1].([]interface{})
+	return parseMessages(messages)
+}
+
+// parseMessages unpacks a list of [id, [field, value, ...]] stream
+// entries — the shape both XREADGROUP and XAUTOCLAIM deliver.
+func parseMessages(messages []interface{}) ([]Alert, error) {
+	var alerts []Alert
+	for _, m := range messages {
+		msg, ok := m.([]interface{})
+		if !ok || len(msg) != 2 {
+			continue
+		}
+		id, _ := msg[0].(string)
+		fields, _ := msg[1].([]interface{})
+
+		alert, err := alertFromFields(id, fields)
+		if err != nil {
+			return nil, fmt.Errorf("message %s: %w", id, err)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
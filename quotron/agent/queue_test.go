@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/redis"
+)
+
+// fakeRedis is a scripted RESP server: it answers dial-time PINGs
+// itself, then pops one canned reply per command and records the
+// commands it saw. The special reply "CLOSE" drops the current
+// connection instead of answering, simulating a server restart;
+// subsequent connections keep consuming the same script.
+type fakeRedis struct {
+	ln net.Listener
+
+	mu      sync.Mutex
+	replies []string
+	cmds    [][]string
+}
+
+func newFakeRedis(t *testing.T, replies ...string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, replies: replies}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeRedis) addr() string { return f.ln.Addr().String() }
+
+func (f *fakeRedis) commands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cmds
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		cmd, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if len(cmd) > 0 && cmd[0] == "PING" {
+			conn.Write([]byte("+PONG\r\n"))
+			continue
+		}
+		f.mu.Lock()
+		f.cmds = append(f.cmds, cmd)
+		reply := "+OK\r\n"
+		if len(f.replies) > 0 {
+			reply, f.replies = f.replies[0], f.replies[1:]
+		}
+		f.mu.Unlock()
+		if reply == "CLOSE" {
+			return
+		}
+		conn.Write([]byte(reply))
+	}
+}
+
+// readCommand parses one RESP command array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	header, err := respLine(r)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(header, "*"))
+	if err != nil {
+		return nil, err
+	}
+	cmd := make([]string, n)
+	for i := range cmd {
+		size, err := respLine(r)
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimPrefix(size, "$"))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, length+2) // include trailing \r\n
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		cmd[i] = string(buf[:length])
+	}
+	return cmd, nil
+}
+
+func respLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+// respBulk and respArray build raw RESP fragments for scripted replies.
+func respBulk(s string) string { return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n" }
+
+func respArray(items ...string) string {
+	reply := "*" + strconv.Itoa(len(items)) + "\r\n"
+	return reply + strings.Join(items, "")
+}
+
+func ensureAgainst(t *testing.T, f *fakeRedis, maxLen int) error {
+	t.Helper()
+	conn, err := redis.Dial(f.addr())
+	if err != nil {
+		t.Fatalf("redis.Dial: %v", err)
+	}
+	defer conn.Close()
+	return ensureConsumerGroup(context.Background(), conn, "quotron:alerts", "agent", maxLen)
+}
+
+func TestEnsureConsumerGroupCreatesStreamAndGroup(t *testing.T) {
+	f := newFakeRedis(t, "+OK\r\n")
+	if err := ensureAgainst(t, f, 0); err != nil {
+		t.Fatalf("ensureConsumerGroup: %v", err)
+	}
+	cmds := f.commands()
+	if len(cmds) != 1 {
+		t.Fatalf("commands = %v, want just the group create", cmds)
+	}
+	want := "XGROUP CREATE quotron:alerts agent $ MKSTREAM"
+	if got := strings.Join(cmds[0], " "); got != want {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+}
+
+func TestEnsureConsumerGroupToleratesBusyGroup(t *testing.T) {
+	// The reply a consumer gets when another one won the creation race.
+	f := newFakeRedis(t, "-BUSYGROUP Consumer Group name already exists\r\n")
+	if err := ensureAgainst(t, f, 0); err != nil {
+		t.Errorf("losing the creation race should not be an error, got %v", err)
+	}
+}
+
+func TestEnsureConsumerGroupSurfacesRealErrors(t *testing.T) {
+	f := newFakeRedis(t, "-ERR unknown command\r\n")
+	err := ensureAgainst(t, f, 0)
+	if err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("err = %v, want the server error surfaced", err)
+	}
+}
+
+func TestEnsureConsumerGroupTrimsWhenAsked(t *testing.T) {
+	f := newFakeRedis(t, "+OK\r\n", ":0\r\n")
+	if err := ensureAgainst(t, f, 1000); err != nil {
+		t.Fatalf("ensureConsumerGroup: %v", err)
+	}
+	cmds := f.commands()
+	if len(cmds) != 2 {
+		t.Fatalf("commands = %v, want group create then trim", cmds)
+	}
+	want := "XTRIM quotron:alerts MAXLEN ~ 1000"
+	if got := strings.Join(cmds[1], " "); got != want {
+		t.Errorf("trim command = %q, want %q", got, want)
+	}
+}
+
+func queueAgainst(t *testing.T, f *fakeRedis) *QueueConsumer {
+	t.Helper()
+	conn, err := redis.Dial(f.addr())
+	if err != nil {
+		t.Fatalf("redis.Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &QueueConsumer{
+		conn:            conn,
+		addr:            f.addr(),
+		stream:          "quotron:alerts",
+		group:           "agent",
+		consumer:        "agent-1",
+		reconnectDelay:  time.Millisecond,
+		reconnectBudget: 3,
+	}
+}
+
+func TestNextDeliversReclaimedAlertsFirst(t *testing.T) {
+	fields := respArray(
+		respBulk("symbol"), respBulk("AAPL"),
+		respBulk("price"), respBulk("190.5"),
+		respBulk("percent_change"), respBulk("-3.2"))
+	reply := respArray(respBulk("0-0"), respArray(respArray(respBulk("1-1"), fields)))
+	f := newFakeRedis(t, reply)
+
+	q := queueAgainst(t, f)
+	q.SetClaimPolicy(time.Minute, 5)
+
+	alerts, err := q.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Symbol != "AAPL" || alerts[0].messageID != "1-1" {
+		t.Fatalf("alerts = %+v, want the reclaimed AAPL alert", alerts)
+	}
+
+	cmds := f.commands()
+	want := "XAUTOCLAIM quotron:alerts agent agent-1 60000 0-0 COUNT 5"
+	if len(cmds) != 1 || strings.Join(cmds[0], " ") != want {
+		t.Errorf("commands = %v, want one %q", cmds, want)
+	}
+}
+
+func TestClaimScansAreThrottled(t *testing.T) {
+	f := newFakeRedis(t, respArray(respBulk("0-0"), respArray()))
+
+	q := queueAgainst(t, f)
+	q.SetClaimPolicy(time.Minute, 0)
+
+	for i := 0; i < 3; i++ {
+		if alerts, err := q.claimPending(); err != nil || alerts != nil {
+			t.Fatalf("scan %d: alerts = %v, err = %v", i, alerts, err)
+		}
+	}
+	cmds := f.commands()
+	if len(cmds) != 1 {
+		t.Fatalf("commands = %v, want one scan within the idle window", cmds)
+	}
+	if got := strings.Join(cmds[0], " "); !strings.HasSuffix(got, "COUNT 10") {
+		t.Errorf("command = %q, want the default batch size", got)
+	}
+}
+
+func TestClaimDisabledWithoutPolicy(t *testing.T) {
+	f := newFakeRedis(t)
+
+	q := queueAgainst(t, f)
+	if alerts, err := q.claimPending(); err != nil || alerts != nil {
+		t.Fatalf("alerts = %v, err = %v, want recovery off", alerts, err)
+	}
+	if cmds := f.commands(); len(cmds) != 0 {
+		t.Errorf("commands = %v, want none with recovery off", cmds)
+	}
+}
+
+func TestNextReconnectsAfterConnectionDrop(t *testing.T) {
+	fields := respArray(
+		respBulk("symbol"), respBulk("MSFT"),
+		respBulk("price"), respBulk("410"),
+		respBulk("percent_change"), respBulk("2.75"))
+	streamReply := respArray(respArray(respBulk("quotron:alerts"),
+		respArray(respArray(respBulk("2-1"), fields))))
+
+	// First read: the server drops the connection (restart). The redial
+	// re-runs the group setup, then the retried read delivers an alert.
+	f := newFakeRedis(t, "CLOSE", "+OK\r\n", streamReply)
+
+	q := queueAgainst(t, f)
+	alerts, err := q.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next after drop: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Symbol != "MSFT" {
+		t.Fatalf("alerts = %+v, want the MSFT alert from the new connection", alerts)
+	}
+
+	cmds := f.commands()
+	if len(cmds) != 3 {
+		t.Fatalf("commands = %v, want read, group re-create, read", cmds)
+	}
+	if cmds[1][0] != "XGROUP" {
+		t.Errorf("command after reconnect = %v, want the group re-created", cmds[1])
+	}
+	if cmds[0][0] != "XREADGROUP" || cmds[2][0] != "XREADGROUP" {
+		t.Errorf("commands = %v, want the read retried on the new connection", cmds)
+	}
+}
+
+func TestReconnectGivesUpAfterBudget(t *testing.T) {
+	f := newFakeRedis(t, "CLOSE")
+	q := queueAgainst(t, f)
+	// Take the server down for good: the drop below cannot be redialed.
+	f.ln.Close()
+
+	_, err := q.Next(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "reconnect attempts") {
+		t.Errorf("err = %v, want the exhausted retry budget surfaced", err)
+	}
+}
+
+func TestReconnectStopsWhenContextEnds(t *testing.T) {
+	f := newFakeRedis(t, "CLOSE")
+	q := queueAgainst(t, f)
+	q.reconnectDelay = time.Minute // long enough that only ctx can end the wait
+	f.ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.Next(ctx)
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Next did not return after cancel")
+	}
+}
+
+func TestPublishAndConsumeShareTheAlertStream(t *testing.T) {
+	published := Alert{
+		Symbol:        "AAPL",
+		Price:         190.5,
+		PercentChange: -3.2,
+		Timestamp:     time.Now().UTC().Truncate(time.Second),
+	}
+
+	// Publish side: XADD must target the shared stream constant.
+	producer := newFakeRedis(t, respBulk("7-1"))
+	conn, err := redis.Dial(producer.addr())
+	if err != nil {
+		t.Fatalf("redis.Dial: %v", err)
+	}
+	defer conn.Close()
+	id, err := publishAlert(context.Background(), conn, events.AlertStream, published)
+	if err != nil {
+		t.Fatalf("publishAlert: %v", err)
+	}
+	xadd := producer.commands()[0]
+	if xadd[0] != "XADD" || xadd[1] != events.AlertStream {
+		t.Fatalf("publish command = %v, want XADD on %s", xadd, events.AlertStream)
+	}
+
+	// Consume side: deliver the captured entry back the way the server
+	// would, through XREADGROUP on the same stream.
+	var fields []string
+	for _, v := range xadd[3:] {
+		fields = append(fields, respBulk(v))
+	}
+	streamReply := respArray(respArray(respBulk(events.AlertStream),
+		respArray(respArray(respBulk(id), respArray(fields...)))))
+	consumer := newFakeRedis(t, streamReply)
+
+	q := queueAgainst(t, consumer)
+	q.stream = events.AlertStream
+	q.group = events.AlertGroup
+	alerts, err := q.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %+v, want the published alert back", alerts)
+	}
+	got := alerts[0]
+	if got.Symbol != published.Symbol || got.Price != published.Price ||
+		got.PercentChange != published.PercentChange || !got.Timestamp.Equal(published.Timestamp) {
+		t.Errorf("consumed alert %+v differs from published %+v", got, published)
+	}
+	read := consumer.commands()[0]
+	if read[len(read)-2] != events.AlertStream {
+		t.Errorf("consume command = %v, want a read of %s", read, events.AlertStream)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when an analysis request is dropped because
+// the per-minute LLM budget is exhausted.
+var ErrRateLimited = errors.New("LLM call rate limit exceeded")
+
+// tokenBucket is a simple continuous-refill token bucket.
+type tokenBucket struct {
+	mu         sync.Mutex
+	capacity   float64
+	tokens     float64
+	perSecond  float64
+	lastRefill time.Time
+}
+
+// newTokenBucket allows ratePerMinute calls per minute with bursts up to
+// the same amount.
+func newTokenBucket(ratePerMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity:   float64(ratePerMinute),
+		tokens:     float64(ratePerMinute),
+		perSecond:  float64(ratePerMinute) / 60,
+		lastRefill: time.Now(),
+	}
+}
+
+// take consumes one token if available.
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.perSecond
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Analyzer is what the alert pipeline needs from an LLM backend: one
+// prompt in, one reply out, plus cumulative usage for the shutdown
+// summary. Both the OpenAI and Ollama clients satisfy it.
+type Analyzer interface {
+	Analyze(ctx context.Context, prompt string) (string, error)
+	Usage() (promptTokens, completionTokens int, estimatedCostUSD float64)
+}
+
+// RateLimitedLLM wraps the LLM client with a calls-per-minute budget so
+// a volatile market can't flood the provider (or the bill). Alerts
+// dropped while the budget is exhausted are counted per symbol and
+// coalesced into the next successful analysis for that symbol.
+type RateLimitedLLM struct {
+	llm    Analyzer
+	bucket *tokenBucket // nil = unlimited
+
+	mu      sync.Mutex
+	dropped map[string]int
+}
+
+// NewRateLimitedLLM wraps llm; ratePerMinute <= 0 disables limiting.
+func NewRateLimitedLLM(llm Analyzer, ratePerMinute int) *RateLimitedLLM {
+	r := &RateLimitedLLM{llm: llm, dropped: map[string]int{}}
+	if ratePerMinute > 0 {
+		r.bucket = newTokenBucket(ratePerMinute)
+	}
+	return r
+}
+
+// Analyze runs one analysis within the rate budget. When the budget is
+// exhausted the request is dropped with ErrRateLimited and remembered,
+// so the next analysis for the same symbol covers the backlog too.
+func (r *RateLimitedLLM) Analyze(ctx context.Context, symbol, prompt string) (string, error) {
+	if r.bucket != nil && !r.bucket.take() {
+		r.mu.Lock()
+		r.dropped[symbol]++
+		count := r.dropped[symbol]
+		r.mu.Unlock()
+		log.Printf("WARNING: dropping LLM analysis for %s (rate limit; %d pending for symbol)", symbol, count)
+		return "", ErrRateLimited
+	}
+
+	if n := r.takeDropped(symbol); n > 0 {
+		prompt = fmt.Sprintf("%s (This also covers %d earlier alerts for %s in the same window.)", prompt, n, symbol)
+	}
+	return r.llm.Analyze(ctx, prompt)
+}
+
+// Usage reports the wrapped client's cumulative token usage and cost.
+func (r *RateLimitedLLM) Usage() (promptTokens, completionTokens int, estimatedCostUSD float64) {
+	return r.llm.Usage()
+}
+
+// takeDropped returns and clears the dropped-alert count for a symbol.
+func (r *RateLimitedLLM) takeDropped(symbol string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.dropped[symbol]
+	delete(r.dropped, symbol)
+	return n
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestTokenBucketExhaustsAndRefills(t *testing.T) {
+	bucket := newTokenBucket(2)
+	if !bucket.take() || !bucket.take() {
+		t.Fatal("bucket should allow its full burst")
+	}
+	if bucket.take() {
+		t.Error("bucket should be empty after burst")
+	}
+}
+
+func TestRateLimitedLLMCoalescesDrops(t *testing.T) {
+	r := NewRateLimitedLLM(nil, 1)
+	// Drain the single token without touching the nil client by going
+	// through the bucket directly.
+	if !r.bucket.take() {
+		t.Fatal("expected initial token")
+	}
+
+	if _, err := r.Analyze(nil, "AAPL", "p"); err != ErrRateLimited {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	if _, err := r.Analyze(nil, "AAPL", "p"); err != ErrRateLimited {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	if n := r.takeDropped("AAPL"); n != 2 {
+		t.Errorf("dropped = %d, want 2", n)
+	}
+	if n := r.takeDropped("AAPL"); n != 0 {
+		t.Errorf("dropped after drain = %d, want 0", n)
+	}
+}
+
+func TestUnlimitedRateHasNoBucket(t *testing.T) {
+	if r := NewRateLimitedLLM(nil, 0); r.bucket != nil {
+		t.Error("rate 0 should disable the bucket")
+	}
+}
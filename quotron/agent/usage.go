@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// modelPrice is the cost per one million tokens, the unit providers
+// publish their pricing in.
+type modelPrice struct {
+	promptPerM     float64
+	completionPerM float64
+}
+
+// modelPrices maps model names to their published prices. Models we
+// don't know about fall back to defaultModelPrice so the estimate is
+// never silently zero.
+var modelPrices = map[string]modelPrice{
+	"gpt-4o-mini":   {promptPerM: 0.15, completionPerM: 0.60},
+	"gpt-4o":        {promptPerM: 2.50, completionPerM: 10.00},
+	"gpt-3.5-turbo": {promptPerM: 0.50, completionPerM: 1.50},
+}
+
+var defaultModelPrice = modelPrice{promptPerM: 1.00, completionPerM: 3.00}
+
+// usageTracker accumulates token counts reported by the LLM API so the
+// agent can print what a session cost on shutdown.
+type usageTracker struct {
+	mu               sync.Mutex
+	promptTokens     int
+	completionTokens int
+}
+
+// add records the usage block from one API response.
+func (u *usageTracker) add(prompt, completion int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.promptTokens += prompt
+	u.completionTokens += completion
+}
+
+// totals returns the accumulated token counts.
+func (u *usageTracker) totals() (prompt, completion int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.promptTokens, u.completionTokens
+}
+
+// priceFor looks up a model's pricing, falling back to the default.
+func priceFor(model string) modelPrice {
+	if p, ok := modelPrices[model]; ok {
+		return p
+	}
+	return defaultModelPrice
+}
+
+// Usage returns the cumulative token counts and the estimated cost in
+// USD based on the per-model price table.
+func (c *LLMClient) Usage() (promptTokens, completionTokens int, estimatedCostUSD float64) {
+	promptTokens, completionTokens = c.usage.totals()
+	price := priceFor(c.model)
+	estimatedCostUSD = float64(promptTokens)/1e6*price.promptPerM +
+		float64(completionTokens)/1e6*price.completionPerM
+	return promptTokens, completionTokens, estimatedCostUSD
+}
+
+// formatUsage renders the usage summary printed on shutdown.
+func formatUsage(prompt, completion int, cost float64) string {
+	return fmt.Sprintf("LLM usage: %d prompt + %d completion tokens, estimated $%.4f",
+		prompt, completion, cost)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const chatWithUsage = `{
+	"choices":[{"message":{"role":"assistant","content":"ok"}}],
+	"usage":{"prompt_tokens":1000,"completion_tokens":500}
+}`
+
+func TestUsageAccumulatesAcrossCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(chatWithUsage))
+	}))
+	defer srv.Close()
+
+	c := testLLMClient(srv.URL)
+	c.model = "gpt-4o-mini"
+	for i := 0; i < 3; i++ {
+		if _, err := c.Analyze(context.Background(), "prompt"); err != nil {
+			t.Fatalf("Analyze: %v", err)
+		}
+	}
+
+	prompt, completion, cost := c.Usage()
+	if prompt != 3000 || completion != 1500 {
+		t.Errorf("tokens = %d/%d, want 3000/1500", prompt, completion)
+	}
+	// 3000 prompt at $0.15/M plus 1500 completion at $0.60/M.
+	want := 3000.0/1e6*0.15 + 1500.0/1e6*0.60
+	if math.Abs(cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+}
+
+func TestPriceForUnknownModelUsesDefault(t *testing.T) {
+	if priceFor("some-future-model") != defaultModelPrice {
+		t.Error("unknown model should fall back to the default price")
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds one delivery attempt so a slow receiver cannot
+// stall alert consumption.
+const webhookTimeout = 5 * time.Second
+
+// webhookMaxAttempts is how many times a delivery is tried; 5xx and
+// transport errors are retried with exponential backoff, 4xx are not.
+const webhookMaxAttempts = 3
+
+// WebhookNotifier POSTs processed alerts to an external integration
+// (Slack, Discord, PagerDuty, ...) as JSON.
+type WebhookNotifier struct {
+	url  string
+	http *http.Client
+}
+
+// NewWebhookNotifier builds a notifier for the given URL.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:  url,
+		http: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// webhookPayload is the JSON body delivered for each alert.
+type webhookPayload struct {
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	PercentChange float64   `json:"percent_change"`
+	Timestamp     time.Time `json:"timestamp"`
+	Analysis      string    `json:"analysis"`
+}
+
+// Notify delivers one alert, retrying transient failures with backoff.
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert, analysis string) error {
+	body, err := json.Marshal(webhookPayload{
+		Symbol:        alert.Symbol,
+		Price:         alert.Price,
+		PercentChange: alert.PercentChange,
+		Timestamp:     alert.Timestamp,
+		Analysis:      analysis,
+	})
+	if err != nil {
+		return err
+	}
+
+	backoff := 500 * time.Millisecond
+	var lastErr error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		lastErr = n.post(ctx, body)
+		if lastErr == nil {
+			return nil
+		}
+		if permanent, ok := lastErr.(*webhookStatusError); ok && permanent.status < 500 {
+			return lastErr // 4xx: retrying won't help
+		}
+		if attempt < webhookMaxAttempts {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return fmt.Errorf("after %d attempts: %w", webhookMaxAttempts, lastErr)
+}
+
+// webhookStatusError is a non-2xx response from the webhook receiver.
+type webhookStatusError struct {
+	status int
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned status %d", e.status)
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &webhookStatusError{status: resp.StatusCode}
+	}
+	return nil
+}
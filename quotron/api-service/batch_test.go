@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// batchServer builds a storage-less mock-mode server with the given
+// batch response cap.
+func batchServer(maxBatchBytes int) *Server {
+	return &Server{
+		cfg:    Config{Mock: true, MaxBatchBytes: maxBatchBytes},
+		client: NewMockDataClient(),
+	}
+}
+
+func postBatch(t *testing.T, s *Server, symbols string) batchResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/quotes/batch",
+		strings.NewReader(`{"symbols":[`+symbols+`]}`))
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return resp
+}
+
+func TestBatchTruncatesAtSizeCap(t *testing.T) {
+	// Room for roughly two encoded quotes, requested five.
+	resp := postBatch(t, batchServer(400), `"AAPL","MSFT","GOOG","AMZN","META"`)
+
+	if !resp.Truncated {
+		t.Fatal("response should be marked truncated")
+	}
+	if len(resp.Quotes) == 0 || len(resp.Quotes) >= 5 {
+		t.Errorf("quotes = %d, want partial results (1-4)", len(resp.Quotes))
+	}
+	if resp.Omitted != 5-len(resp.Quotes) {
+		t.Errorf("omitted = %d, want %d", resp.Omitted, 5-len(resp.Quotes))
+	}
+}
+
+func TestBatchUnderCapIsComplete(t *testing.T) {
+	resp := postBatch(t, batchServer(0), `"AAPL","MSFT","GOOG"`)
+
+	if resp.Truncated {
+		t.Error("response under the cap should not be truncated")
+	}
+	if len(resp.Quotes) != 3 {
+		t.Errorf("quotes = %d, want 3", len(resp.Quotes))
+	}
+}
+
+func TestBatchGetVariant(t *testing.T) {
+	s := batchServer(0)
+	req := httptest.NewRequest(http.MethodGet, "/api/quotes/batch?symbols=AAPL,%20msft,", nil)
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Quotes) != 2 {
+		t.Fatalf("quotes = %d, want 2", len(resp.Quotes))
+	}
+	if resp.Quotes[1].Symbol != "MSFT" {
+		t.Errorf("second symbol = %s, want MSFT (upcased)", resp.Quotes[1].Symbol)
+	}
+}
+
+func TestBatchGetWithoutSymbols(t *testing.T) {
+	s := batchServer(0)
+	req := httptest.NewRequest(http.MethodGet, "/api/quotes/batch", nil)
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestBatchRejectsTooManySymbols(t *testing.T) {
+	symbols := make([]string, maxBatchSymbols+1)
+	for i := range symbols {
+		symbols[i] = `"S` + strconv.Itoa(i) + `"`
+	}
+	s := batchServer(0)
+	req := httptest.NewRequest(http.MethodPost, "/api/quotes/batch",
+		strings.NewReader(`{"symbols":[`+strings.Join(symbols, ",")+`]}`))
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 for %d symbols", rec.Code, len(symbols))
+	}
+}
+
+// flakyClient fails quote fetches for the listed symbols.
+type flakyClient struct {
+	*MockDataClient
+	fail map[string]bool
+}
+
+func (c flakyClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	if c.fail[symbol] {
+		return nil, errors.New("upstream exploded")
+	}
+	return c.MockDataClient.GetStockQuote(ctx, symbol)
+}
+
+// panickyClient panics on quote fetches for the listed symbols.
+type panickyClient struct {
+	*MockDataClient
+	boom map[string]bool
+}
+
+func (c panickyClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	if c.boom[symbol] {
+		panic("client bug for " + symbol)
+	}
+	return c.MockDataClient.GetStockQuote(ctx, symbol)
+}
+
+func postBatchRaw(s *Server, symbols string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/quotes/batch",
+		strings.NewReader(`{"symbols":[`+symbols+`]}`))
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+	return rec
+}
+
+func TestBatchPartialFailureReturns207(t *testing.T) {
+	s := batchServer(0)
+	s.client = flakyClient{NewMockDataClient(), map[string]bool{"MSFT": true}}
+
+	rec := postBatchRaw(s, `"AAPL","MSFT"`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quotes) != 1 || resp.Errors["MSFT"] == "" {
+		t.Errorf("response %+v should hold one quote and the MSFT error", resp)
+	}
+}
+
+func TestBatchSurvivesPanickingFetch(t *testing.T) {
+	s := batchServer(0)
+	s.client = panickyClient{NewMockDataClient(), map[string]bool{"MSFT": true}}
+
+	rec := postBatchRaw(s, `"AAPL","MSFT"`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207: the panic should become MSFT's error", rec.Code)
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quotes) != 1 || resp.Errors["MSFT"] == "" {
+		t.Errorf("response %+v should hold the AAPL quote and an MSFT error", resp)
+	}
+}
+
+func TestBatchTotalFailureReturns502(t *testing.T) {
+	s := batchServer(0)
+	s.client = flakyClient{NewMockDataClient(), map[string]bool{"AAPL": true, "MSFT": true}}
+
+	if rec := postBatchRaw(s, `"AAPL","MSFT"`); rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502 when every symbol fails", rec.Code)
+	}
+}
+
+func TestBatchCapDisabled(t *testing.T) {
+	resp := postBatch(t, batchServer(-1), `"AAPL","MSFT","GOOG","AMZN","META"`)
+
+	if resp.Truncated || len(resp.Quotes) != 5 {
+		t.Errorf("cap disabled: truncated = %v, quotes = %d, want all 5",
+			resp.Truncated, len(resp.Quotes))
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// batchStatusHandler serves GET /api/batches/{id}: the data_batches row
+// for one ingested batch, so submitters can poll a load's outcome
+// instead of treating ingestion as fire-and-forget.
+func (s *Server) batchStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/api/batches/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusBadRequest, "invalid batch id")
+		return
+	}
+
+	record, err := s.storage.GetDataBatch(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "unknown batch")
+		return
+	}
+	if err != nil {
+		reqLog(r.Context(), "ERROR: loading batch %s: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to load batch")
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
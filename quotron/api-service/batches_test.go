@@ -0,0 +1,49 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func getBatchStatus(s *Server, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.batchStatusHandler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestBatchStatusUnknownIDIs404(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{storage: NewStorage(db, ConflictIgnore)}
+
+	if rec := getBatchStatus(s, "/api/batches/deadbeef"); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 for a batch the table has no row for", rec.Code)
+	}
+}
+
+func TestBatchStatusRejectsBadPaths(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{storage: NewStorage(db, ConflictIgnore)}
+
+	for _, path := range []string{"/api/batches/", "/api/batches/a/b"} {
+		if rec := getBatchStatus(s, path); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", path, rec.Code)
+		}
+	}
+}
+
+func TestBatchStatusWithoutStorageIs503(t *testing.T) {
+	s := &Server{}
+	if rec := getBatchStatus(s, "/api/batches/deadbeef"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// compareResponse is the envelope for /api/quotes/compare: the quotes
+// sorted by day change so a comparison UI can render them as-is, plus
+// the symbols that moved most either way.
+type compareResponse struct {
+	// Quotes is sorted by change percent, best performer first.
+	Quotes []*models.StockQuote `json:"quotes"`
+	Errors map[string]string    `json:"errors,omitempty"`
+	// Best and Worst name the symbols with the highest and lowest day
+	// change among the quotes that resolved.
+	Best  string `json:"best,omitempty"`
+	Worst string `json:"worst,omitempty"`
+}
+
+// compareQuotesHandler serves POST /api/quotes/compare: a focused
+// convenience over the generic batch endpoint for side-by-side symbol
+// comparison. Change percent is the normalized metric — it is directly
+// comparable across symbols regardless of price level.
+func (s *Server) compareQuotesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	symbols, ok := batchSymbolsFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	batch := s.fetchBatch(r, symbols)
+	resp := compareResponse{Quotes: batch.Quotes, Errors: batch.Errors}
+	sort.SliceStable(resp.Quotes, func(i, j int) bool {
+		return resp.Quotes[i].ChangePercent > resp.Quotes[j].ChangePercent
+	})
+	if len(resp.Quotes) > 0 {
+		resp.Best = resp.Quotes[0].Symbol
+		resp.Worst = resp.Quotes[len(resp.Quotes)-1].Symbol
+	}
+
+	status := http.StatusOK
+	if len(resp.Errors) > 0 {
+		status = http.StatusMultiStatus
+		if len(resp.Quotes) == 0 {
+			status = http.StatusBadGateway
+		}
+	}
+	writeJSON(w, status, resp)
+}
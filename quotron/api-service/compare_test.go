@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func postCompare(t *testing.T, s *Server, symbols string) (*httptest.ResponseRecorder, compareResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/quotes/compare",
+		strings.NewReader(`{"symbols":[`+symbols+`]}`))
+	rec := httptest.NewRecorder()
+	s.compareQuotesHandler(rec, req)
+
+	var resp compareResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return rec, resp
+}
+
+func TestCompareSortsByChangePercent(t *testing.T) {
+	rec, resp := postCompare(t, batchServer(0), `"AAPL","MSFT","GOOG"`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if len(resp.Quotes) != 3 {
+		t.Fatalf("quotes = %d, want 3", len(resp.Quotes))
+	}
+	if !sort.SliceIsSorted(resp.Quotes, func(i, j int) bool {
+		return resp.Quotes[i].ChangePercent > resp.Quotes[j].ChangePercent
+	}) {
+		t.Error("quotes should be sorted by change percent, best first")
+	}
+	if resp.Best != resp.Quotes[0].Symbol {
+		t.Errorf("best = %q, want the first quote's symbol %q", resp.Best, resp.Quotes[0].Symbol)
+	}
+	if resp.Worst != resp.Quotes[len(resp.Quotes)-1].Symbol {
+		t.Errorf("worst = %q, want the last quote's symbol", resp.Worst)
+	}
+}
+
+func TestComparePartialFailureIsMultiStatus(t *testing.T) {
+	s := batchServer(0)
+	s.client = flakyClient{MockDataClient: NewMockDataClient(), fail: map[string]bool{"MSFT": true}}
+
+	rec, resp := postCompare(t, s, `"AAPL","MSFT"`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207", rec.Code)
+	}
+	if resp.Best != "AAPL" || resp.Worst != "AAPL" {
+		t.Errorf("best/worst = %q/%q, want the one resolved symbol", resp.Best, resp.Worst)
+	}
+	if _, ok := resp.Errors["MSFT"]; !ok {
+		t.Error("failed symbol should be reported in errors")
+	}
+}
+
+func TestCompareRejectsGet(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/quotes/compare?symbols=AAPL", nil)
+	rec := httptest.NewRecorder()
+	batchServer(0).compareQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405 for GET", rec.Code)
+	}
+}
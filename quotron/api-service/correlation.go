@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minPairedPoints is the fewest paired daily observations we accept
+// before a correlation is statistically meaningless.
+const minPairedPoints = 10
+
+// CorrelationResult is the payload for /api/correlation.
+type CorrelationResult struct {
+	SymbolA string `json:"symbol_a"`
+	SymbolB string `json:"symbol_b"`
+	Window  string `json:"window"`
+	// Correlation is the Pearson correlation of paired daily returns.
+	Correlation        float64 `json:"correlation"`
+	PairedObservations int     `json:"paired_observations"`
+}
+
+// correlationHandler serves /api/correlation?a=AAPL&b=MSFT&window=60d:
+// both stored series collapsed to daily closes, joined on date (unmatched
+// days dropped), Pearson correlation of the paired daily returns.
+func (s *Server) correlationHandler(w http.ResponseWriter, r *http.Request) {
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "storage not available")
+		return
+	}
+
+	a := strings.ToUpper(r.URL.Query().Get("a"))
+	b := strings.ToUpper(r.URL.Query().Get("b"))
+	if a == "" || b == "" {
+		writeError(w, http.StatusBadRequest, "both a and b symbols are required")
+		return
+	}
+
+	windowArg := r.URL.Query().Get("window")
+	if windowArg == "" {
+		windowArg = "60d"
+	}
+	window, err := parseStatsWindow(windowArg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	since := time.Now().UTC().Add(-window)
+	closesA, err := s.dailyCloses(r.Context(), a, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load series for "+a+": "+err.Error())
+		return
+	}
+	closesB, err := s.dailyCloses(r.Context(), b, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load series for "+b+": "+err.Error())
+		return
+	}
+
+	returnsA, returnsB := pairedDailyReturns(closesA, closesB)
+	if len(returnsA) < minPairedPoints {
+		writeError(w, http.StatusUnprocessableEntity,
+			fmt.Sprintf("only %d paired daily observations for %s/%s in %s; need at least %d",
+				len(returnsA), a, b, windowArg, minPairedPoints))
+		return
+	}
+
+	writeJSONWithETag(w, r, &CorrelationResult{
+		SymbolA:            a,
+		SymbolB:            b,
+		Window:             windowArg,
+		Correlation:        pearson(returnsA, returnsB),
+		PairedObservations: len(returnsA),
+	})
+}
+
+// dailyCloses collapses a stored series to one closing price per UTC day.
+func (s *Server) dailyCloses(ctx context.Context, symbol string, since time.Time) (map[string]float64, error) {
+	quotes, err := s.storage.GetQuotesSince(ctx, symbol, since)
+	if err != nil {
+		return nil, err
+	}
+	closes := map[string]float64{}
+	// GetQuotesSince is ordered ASC, so the last write per day wins.
+	for _, q := range quotes {
+		closes[q.Timestamp.UTC().Format("2006-01-02")] = q.Price
+	}
+	return closes, nil
+}
+
+// pairedDailyReturns joins two daily-close maps on date, drops unmatched
+// days, and returns the aligned day-over-day return series.
+func pairedDailyReturns(a, b map[string]float64) (returnsA, returnsB []float64) {
+	var days []string
+	for day := range a {
+		if _, ok := b[day]; ok {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+
+	for i := 1; i < len(days); i++ {
+		prevA, prevB := a[days[i-1]], b[days[i-1]]
+		if prevA == 0 || prevB == 0 {
+			continue
+		}
+		returnsA = append(returnsA, a[days[i]]/prevA-1)
+		returnsB = append(returnsB, b[days[i]]/prevB-1)
+	}
+	return returnsA, returnsB
+}
+
+// pearson computes the Pearson correlation coefficient of two equal-length
+// series.
+func pearson(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultCryptoQuote is the quote currency bare coin symbols pair with
+// when neither the request nor --crypto-quote says otherwise.
+const defaultCryptoQuote = "USD"
+
+// validQuoteCurrency reports whether a currency code looks like one:
+// three to five letters, the range ISO codes and stablecoin tickers
+// (USDT) live in.
+func validQuoteCurrency(code string) bool {
+	if len(code) < 3 || len(code) > 5 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// cryptoQuote picks the quote currency for a request: the ?quote=
+// parameter when given, the --crypto-quote default otherwise.
+func (s *Server) cryptoQuote(r *http.Request) string {
+	if q := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("quote"))); q != "" {
+		return q
+	}
+	if s.cfg.CryptoQuote != "" {
+		return s.cfg.CryptoQuote
+	}
+	return defaultCryptoQuote
+}
+
+// normalizeCryptoSymbol maps a coin name onto the upstream's pair
+// notation: bare "BTC" becomes "BTC-<quote>", while an explicit pair
+// like "ETH-EUR" passes through unchanged regardless of the requested
+// quote currency.
+func normalizeCryptoSymbol(symbol, quote string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" || strings.Contains(symbol, "-") {
+		return symbol
+	}
+	return symbol + "-" + quote
+}
+
+// cryptosHandler serves GET /api/cryptos: the configured default coin
+// set in one response, mirroring /api/indices for equities. Deployments
+// track different coins with --default-cryptos instead of editing the
+// dashboard's embedded script.
+func (s *Server) cryptosHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	quote := s.cryptoQuote(r)
+	if !validQuoteCurrency(quote) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid quote currency %q", quote))
+		return
+	}
+
+	coins := make([]string, len(s.cfg.DefaultCryptos))
+	for i, coin := range s.cfg.DefaultCryptos {
+		coins[i] = normalizeCryptoSymbol(coin, quote)
+	}
+	resp := s.fetchBatch(r, coins)
+	if status := batchStatus(resp); status != http.StatusOK {
+		writeJSON(w, status, resp)
+		return
+	}
+	writeJSONConditional(w, r, batchETag(coins, resp), resp)
+}
+
+// cryptoBatchHandler serves /api/crypto/batch in the same two shapes as
+// /api/quotes/batch, so dashboards fetching several coins do it in one
+// request. Symbols are normalized to pairs against the requested quote
+// currency first, and the usual batch semantics apply: 207 for partial
+// failures, 502 when every coin fails.
+func (s *Server) cryptoBatchHandler(w http.ResponseWriter, r *http.Request) {
+	quote := s.cryptoQuote(r)
+	if !validQuoteCurrency(quote) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid quote currency %q", quote))
+		return
+	}
+	symbols, ok := batchSymbolsFromRequest(w, r)
+	if !ok {
+		return
+	}
+	for i, symbol := range symbols {
+		symbols[i] = normalizeCryptoSymbol(symbol, quote)
+	}
+
+	resp := s.fetchBatch(r, symbols)
+	if status := batchStatus(resp); status != http.StatusOK {
+		writeJSON(w, status, resp)
+		return
+	}
+	writeJSONConditional(w, r, batchETag(symbols, resp), resp)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postCryptoBatch(s *Server, symbols string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/crypto/batch",
+		strings.NewReader(`{"symbols":[`+symbols+`]}`))
+	rec := httptest.NewRecorder()
+	s.cryptoBatchHandler(rec, req)
+	return rec
+}
+
+func TestNormalizeCryptoSymbol(t *testing.T) {
+	cases := []struct {
+		symbol, quote, want string
+	}{
+		{"btc", "USD", "BTC-USD"},
+		{"BTC", "USD", "BTC-USD"},
+		{" eth ", "USD", "ETH-USD"},
+		{"btc", "EUR", "BTC-EUR"},
+		{"sol", "USDT", "SOL-USDT"},
+		// Explicit pairs win over the requested quote currency.
+		{"ETH-EUR", "USD", "ETH-EUR"},
+		{"", "USD", ""},
+	}
+	for _, c := range cases {
+		if got := normalizeCryptoSymbol(c.symbol, c.quote); got != c.want {
+			t.Errorf("normalizeCryptoSymbol(%q, %q) = %q, want %q", c.symbol, c.quote, got, c.want)
+		}
+	}
+}
+
+func TestValidQuoteCurrency(t *testing.T) {
+	for code, want := range map[string]bool{
+		"USD": true, "EUR": true, "USDT": true,
+		"US": false, "TOOLONG": false, "U$D": false, "": false,
+	} {
+		if got := validQuoteCurrency(code); got != want {
+			t.Errorf("validQuoteCurrency(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestCryptoBatchNormalizesPairs(t *testing.T) {
+	rec := postCryptoBatch(batchServer(0), `"btc","ETH-EUR"`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quotes) != 2 {
+		t.Fatalf("got %d quotes, want 2", len(resp.Quotes))
+	}
+	if resp.Quotes[0].Symbol != "BTC-USD" || resp.Quotes[1].Symbol != "ETH-EUR" {
+		t.Errorf("symbols = %s, %s; want BTC-USD, ETH-EUR",
+			resp.Quotes[0].Symbol, resp.Quotes[1].Symbol)
+	}
+	for _, q := range resp.Quotes {
+		if q.High24h == nil || q.Low24h == nil {
+			t.Errorf("%s: crypto quote should carry the 24h range, got high=%v low=%v",
+				q.Symbol, q.High24h, q.Low24h)
+		}
+	}
+}
+
+func TestCryptoBatchQuoteCurrencyOverride(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/crypto/batch?quote=eur",
+		strings.NewReader(`{"symbols":["btc","ETH-USD"]}`))
+	rec := httptest.NewRecorder()
+	batchServer(0).cryptoBatchHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Quotes[0].Symbol != "BTC-EUR" || resp.Quotes[1].Symbol != "ETH-USD" {
+		t.Errorf("symbols = %s, %s; want BTC-EUR, ETH-USD",
+			resp.Quotes[0].Symbol, resp.Quotes[1].Symbol)
+	}
+}
+
+func TestCryptoBatchConfiguredQuoteCurrency(t *testing.T) {
+	s := batchServer(0)
+	s.cfg.CryptoQuote = "GBP"
+	rec := postCryptoBatch(s, `"btc"`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Quotes[0].Symbol != "BTC-GBP" {
+		t.Errorf("symbol = %s, want BTC-GBP", resp.Quotes[0].Symbol)
+	}
+}
+
+func TestCryptoBatchRejectsBadQuoteCurrency(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/crypto/batch?quote=dollars",
+		strings.NewReader(`{"symbols":["btc"]}`))
+	rec := httptest.NewRecorder()
+	batchServer(0).cryptoBatchHandler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCryptoBatchPartialFailureReturns207(t *testing.T) {
+	s := batchServer(0)
+	s.client = flakyClient{NewMockDataClient(), map[string]bool{"ETH-USD": true}}
+
+	rec := postCryptoBatch(s, `"BTC","ETH"`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quotes) != 1 || resp.Errors["ETH-USD"] == "" {
+		t.Errorf("response %+v should hold the BTC quote and the ETH-USD error", resp)
+	}
+}
+
+func TestCryptoBatchWithoutSymbols(t *testing.T) {
+	if rec := postCryptoBatch(batchServer(0), ``); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCryptosServesConfiguredCoinSet(t *testing.T) {
+	s := batchServer(0)
+	s.cfg.DefaultCryptos = []string{"BTC", "ETH"}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cryptos", nil)
+	rec := httptest.NewRecorder()
+	s.cryptosHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quotes) != 2 {
+		t.Fatalf("quotes = %d, want the 2 configured coins", len(resp.Quotes))
+	}
+	if resp.Quotes[0].Symbol != "BTC-USD" || resp.Quotes[1].Symbol != "ETH-USD" {
+		t.Errorf("symbols = %s, %s; want bare coins normalized to -USD pairs",
+			resp.Quotes[0].Symbol, resp.Quotes[1].Symbol)
+	}
+}
+
+func TestCryptosHonorsQuoteOverride(t *testing.T) {
+	s := batchServer(0)
+	s.cfg.DefaultCryptos = []string{"BTC"}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/cryptos?quote=eur", nil)
+	rec := httptest.NewRecorder()
+	s.cryptosHandler(rec, req)
+
+	var resp batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Quotes[0].Symbol != "BTC-EUR" {
+		t.Errorf("symbol = %s, want BTC-EUR", resp.Quotes[0].Symbol)
+	}
+}
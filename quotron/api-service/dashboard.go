@@ -0,0 +1,117 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// defaultSymbolList is the equity set the dashboard polls when
+// --default-symbols is not given.
+const defaultSymbolList = "AAPL,MSFT,GOOG,AMZN,NVDA"
+
+// defaultCryptoList is the coin set the dashboard aggregate serves when
+// --default-cryptos is not given.
+const defaultCryptoList = "BTC,ETH,SOL,XRP,DOGE"
+
+// defaultDashboardRefresh is how often the dashboard polls for fresh
+// quotes; minDashboardRefresh keeps operators from configuring an
+// interval that hammers the upstreams.
+const (
+	defaultDashboardRefresh = 5 * time.Second
+	minDashboardRefresh     = 5 * time.Second
+)
+
+//go:embed templates/dashboard.html
+var dashboardFS embed.FS
+
+var dashboardTmpl = template.Must(template.ParseFS(dashboardFS, "templates/dashboard.html"))
+
+// dashboardData is the server-injected configuration the dashboard
+// template renders into its inline script.
+type dashboardData struct {
+	Symbols   []string
+	RefreshMS int
+}
+
+// validateDashboardRefresh rejects refresh intervals short enough to
+// hammer the upstreams.
+func validateDashboardRefresh(d time.Duration) error {
+	if d < minDashboardRefresh {
+		return fmt.Errorf("dashboard refresh %v is below the minimum %v", d, minDashboardRefresh)
+	}
+	return nil
+}
+
+// dashboardHandler serves the single-page market dashboard, rendering
+// the embedded template with the configured symbol set and refresh
+// interval.
+func (s *Server) dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	refresh := s.cfg.DashboardRefresh
+	if refresh == 0 {
+		refresh = defaultDashboardRefresh
+	}
+	data := dashboardData{
+		Symbols:   s.cfg.DefaultSymbols,
+		RefreshMS: int(refresh / time.Millisecond),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTmpl.Execute(w, data); err != nil {
+		log.Printf("ERROR: rendering dashboard: %v", err)
+	}
+}
+
+// dashboardPayload is the consolidated response of /api/dashboard: one
+// round-trip instead of a fetch per index, coin, and status. IndexErrors
+// and CryptoErrors carry per-item failures so partial outages still
+// render what resolved.
+type dashboardPayload struct {
+	Indices      []*models.MarketIndex `json:"indices"`
+	Crypto       []*models.StockQuote  `json:"crypto"`
+	Status       map[string]string     `json:"status"`
+	IndexErrors  map[string]string     `json:"index_errors,omitempty"`
+	CryptoErrors map[string]string     `json:"crypto_errors,omitempty"`
+}
+
+// dashboardDataHandler serves GET /api/dashboard: the default indices,
+// the default crypto set, and the service status, fetched concurrently
+// and returned as one object.
+func (s *Server) dashboardDataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	payload := dashboardPayload{Status: s.serviceStatus()}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		payload.Indices, payload.IndexErrors = s.fetchIndices(r, s.cfg.DefaultIndices)
+	}()
+	go func() {
+		defer wg.Done()
+		quote := s.cryptoQuote(r)
+		coins := make([]string, len(s.cfg.DefaultCryptos))
+		for i, coin := range s.cfg.DefaultCryptos {
+			coins[i] = normalizeCryptoSymbol(coin, quote)
+		}
+		batch := s.fetchBatch(r, coins)
+		payload.Crypto, payload.CryptoErrors = batch.Quotes, batch.Errors
+	}()
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, payload)
+}
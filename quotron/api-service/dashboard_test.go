@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardInjectsConfiguredSymbols(t *testing.T) {
+	s := &Server{cfg: Config{DefaultSymbols: []string{"TSLA", "AMD"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	s.dashboardHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{`"TSLA"`, `"AMD"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("dashboard should inject %s into the script", want)
+		}
+	}
+	if !strings.Contains(body, "REFRESH_MS =  5000 ") {
+		t.Errorf("dashboard should inject the refresh interval")
+	}
+}
+
+func TestDashboardUsesConfiguredRefresh(t *testing.T) {
+	s := &Server{cfg: Config{DashboardRefresh: 30 * time.Second}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	s.dashboardHandler(rec, req)
+
+	if !strings.Contains(rec.Body.String(), "REFRESH_MS =  30000 ") {
+		t.Error("dashboard should inject the configured refresh interval")
+	}
+}
+
+func TestDashboardAggregateServesOneConsolidatedObject(t *testing.T) {
+	s := &Server{
+		cfg: Config{
+			Mock:           true,
+			DefaultIndices: []string{"^GSPC", "^DJI"},
+			DefaultCryptos: []string{"BTC", "ETH"},
+		},
+		client: NewMockDataClient(),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/dashboard", nil)
+	rec := httptest.NewRecorder()
+	s.dashboardDataHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var payload dashboardPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Indices) != 2 {
+		t.Errorf("indices = %d, want 2", len(payload.Indices))
+	}
+	if len(payload.Crypto) != 2 {
+		t.Fatalf("crypto = %d, want 2", len(payload.Crypto))
+	}
+	if payload.Crypto[0].Symbol != "BTC-USD" && payload.Crypto[1].Symbol != "BTC-USD" {
+		t.Errorf("coins should be normalized to pairs, got %s and %s",
+			payload.Crypto[0].Symbol, payload.Crypto[1].Symbol)
+	}
+	if payload.Status["status"] != "ok" {
+		t.Errorf("status = %v, want the service health summary", payload.Status)
+	}
+}
+
+func TestDashboardAggregateReportsPartialFailures(t *testing.T) {
+	s := &Server{
+		cfg: Config{
+			Mock:           true,
+			DefaultIndices: []string{"^GSPC"},
+			DefaultCryptos: []string{"BTC", "ETH"},
+		},
+		client: flakyClient{MockDataClient: NewMockDataClient(), fail: map[string]bool{"BTC-USD": true}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/dashboard", nil)
+	rec := httptest.NewRecorder()
+	s.dashboardDataHandler(rec, req)
+
+	var payload dashboardPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Crypto) != 1 {
+		t.Errorf("crypto = %d, want the one coin that resolved", len(payload.Crypto))
+	}
+	if _, ok := payload.CryptoErrors["BTC-USD"]; !ok {
+		t.Errorf("crypto_errors = %v, want the failed coin reported", payload.CryptoErrors)
+	}
+	if len(payload.Indices) != 1 {
+		t.Errorf("indices = %d, the index fetch should be unaffected", len(payload.Indices))
+	}
+}
+
+func TestValidateDashboardRefresh(t *testing.T) {
+	if err := validateDashboardRefresh(time.Second); err == nil {
+		t.Error("1s refresh should be rejected")
+	}
+	if err := validateDashboardRefresh(30 * time.Second); err != nil {
+		t.Errorf("30s refresh should be accepted: %v", err)
+	}
+}
+
+func TestDashboardRejectsOtherPaths(t *testing.T) {
+	s := &Server{}
+	req := httptest.NewRequest(http.MethodGet, "/nope", nil)
+	rec := httptest.NewRecorder()
+	s.dashboardHandler(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
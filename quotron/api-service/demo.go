@@ -0,0 +1,14 @@
+package main
+
+// applyDemoMode rewrites the config for a fully self-contained process:
+// synthetic data from the mock client, no database, no trace export —
+// nothing external at all. For demos and CI, where `api-service -demo`
+// must come up working on a bare machine.
+func applyDemoMode(cfg *Config) {
+	cfg.Mock = true
+	cfg.DatabaseURL = ""
+	cfg.ReplicaURL = ""
+	cfg.AutoStore = false
+	cfg.TraceEndpoint = ""
+	cfg.StoreBackend = ""
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// TestDemoModeServesQuotesEndToEnd boots the full server (NewServer,
+// real route table, middleware-free mux) in demo mode and pulls quotes
+// through it, proving the process works with nothing external attached.
+func TestDemoModeServesQuotesEndToEnd(t *testing.T) {
+	cfg := Config{}
+	applyDemoMode(&cfg)
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer in demo mode: %v", err)
+	}
+	if server.storage != nil {
+		t.Fatal("demo mode must not open storage")
+	}
+
+	ts := httptest.NewServer(server.setupRoutes())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/quote/AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/quote/AAPL = %d, want 200", resp.StatusCode)
+	}
+	var quote models.StockQuote
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		t.Fatal(err)
+	}
+	if quote.Symbol != "AAPL" || quote.Price <= 0 {
+		t.Errorf("quote = %+v, want a synthetic AAPL quote with a price", quote)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/quotes/batch?symbols=AAPL,MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/quotes/batch = %d, want 200", resp.StatusCode)
+	}
+	var batch batchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Quotes) != 2 {
+		t.Errorf("batch quotes = %d, want 2", len(batch.Quotes))
+	}
+
+	resp, err = http.Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /health = %d, want 200", resp.StatusCode)
+	}
+}
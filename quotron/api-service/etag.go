@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// quoteETag computes a weak ETag covering every field of the payload, so
+// the tag changes exactly when the returned quote changes.
+func quoteETag(v interface{}) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(body)
+	return fmt.Sprintf(`W/"%x"`, sum[:8]), nil
+}
+
+// batchETag computes a weak ETag for a batch response from the sorted
+// symbol set and the freshest quote timestamp, so the tag is stable
+// across symbol orderings and changes exactly when newer data arrives.
+func batchETag(symbols []string, resp batchResponse) string {
+	sorted := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		sorted[i] = strings.ToUpper(strings.TrimSpace(symbol))
+	}
+	sort.Strings(sorted)
+
+	var freshest time.Time
+	for _, quote := range resp.Quotes {
+		if quote.Timestamp.After(freshest) {
+			freshest = quote.Timestamp
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(sorted, ",") + "|" + freshest.UTC().Format(time.RFC3339Nano)))
+	return fmt.Sprintf(`W/"%x"`, sum[:8])
+}
+
+// writeJSONConditional serves v under a precomputed ETag, answering 304
+// Not Modified when the client already holds it.
+func writeJSONConditional(w http.ResponseWriter, r *http.Request, etag string, v interface{}) {
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// writeJSONWithETag sets the ETag header and honors If-None-Match,
+// answering 304 Not Modified when the client already holds this payload.
+// Falls back to a plain JSON response if the tag cannot be computed.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v interface{}) {
+	etag, err := quoteETag(v)
+	if err != nil {
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
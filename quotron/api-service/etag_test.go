@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func batchAt(ts time.Time) batchResponse {
+	return batchResponse{Quotes: []*models.StockQuote{
+		{Symbol: "AAPL", Price: 100, Timestamp: ts},
+		{Symbol: "MSFT", Price: 200, Timestamp: ts.Add(-time.Minute)},
+	}}
+}
+
+func TestBatchETagIgnoresSymbolOrder(t *testing.T) {
+	ts := time.Date(2026, 8, 27, 12, 0, 0, 0, time.UTC)
+	resp := batchAt(ts)
+
+	a := batchETag([]string{"AAPL", "MSFT"}, resp)
+	b := batchETag([]string{"msft", " aapl"}, resp)
+	if a != b {
+		t.Errorf("etag should be order- and case-insensitive: %q vs %q", a, b)
+	}
+}
+
+func TestBatchETagChangesWithFreshness(t *testing.T) {
+	ts := time.Date(2026, 8, 27, 12, 0, 0, 0, time.UTC)
+	symbols := []string{"AAPL", "MSFT"}
+
+	a := batchETag(symbols, batchAt(ts))
+	b := batchETag(symbols, batchAt(ts.Add(time.Second)))
+	if a == b {
+		t.Error("etag should change when a fresher quote arrives")
+	}
+}
+
+func TestBatchConditionalRequestReturns304(t *testing.T) {
+	ts := time.Date(2026, 8, 27, 12, 0, 0, 0, time.UTC)
+	resp := batchAt(ts)
+	etag := batchETag([]string{"AAPL", "MSFT"}, resp)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/quotes/batch?symbols=AAPL,MSFT", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	writeJSONConditional(rec, req, etag, resp)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("304 response should have no body, got %q", rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("ETag header = %q, want %q", rec.Header().Get("ETag"), etag)
+	}
+}
+
+func TestBatchHandlerSetsETag(t *testing.T) {
+	s := batchServer(0)
+	req := httptest.NewRequest(http.MethodGet, "/api/quotes/batch?symbols=AAPL", nil)
+	rec := httptest.NewRecorder()
+	s.batchQuotesHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("batch response should carry an ETag")
+	}
+}
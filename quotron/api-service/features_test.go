@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDisabledFeatureRouteReturns404(t *testing.T) {
+	s := &Server{
+		cfg:    Config{Mock: true, DisabledFeatures: []string{"correlation"}},
+		client: NewMockDataClient(),
+	}
+	mux := s.setupRoutes()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/correlation?a=AAPL&b=MSFT", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled correlation route: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDisabledIntradaySubrouteReturns404(t *testing.T) {
+	s := &Server{
+		cfg:    Config{Mock: true, DisabledFeatures: []string{"intraday"}},
+		client: NewMockDataClient(),
+	}
+
+	rec := httptest.NewRecorder()
+	s.getQuoteHandler(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL/intraday", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled intraday subroute: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestFeaturesEnabledByDefault(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient()}
+	for _, name := range knownFeatures {
+		if !s.featureEnabled(name) {
+			t.Errorf("feature %s should default to enabled", name)
+		}
+	}
+}
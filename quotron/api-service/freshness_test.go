@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func TestCheckFreshness(t *testing.T) {
+	s := &Server{cfg: Config{MaxQuoteAge: time.Minute}}
+
+	fresh := &models.StockQuote{Symbol: "AAPL", Timestamp: time.Now().Add(-time.Second)}
+	if err := s.checkFreshness(fresh); err != nil {
+		t.Errorf("fresh quote rejected: %v", err)
+	}
+
+	stale := &models.StockQuote{Symbol: "AAPL", Timestamp: time.Now().Add(-time.Hour)}
+	if err := s.checkFreshness(stale); err == nil {
+		t.Error("hour-old quote should be rejected with a 1m maximum")
+	}
+}
+
+func TestCheckFreshnessDisabledByDefault(t *testing.T) {
+	s := &Server{} // zero MaxQuoteAge
+	ancient := &models.StockQuote{Symbol: "AAPL", Timestamp: time.Now().Add(-24 * time.Hour)}
+	if err := s.checkFreshness(ancient); err != nil {
+		t.Errorf("gate disabled: %v", err)
+	}
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/client"
+	"github.com/we-be/tiny-ria/quotron/models"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// writeJSON serializes v to the response with the standard headers.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("ERROR: encoding response: %v", err)
+	}
+}
+
+// writeError sends a JSON error body with the given status.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// upstreamStatus maps a failed upstream fetch to the status the client
+// should see: 504 when our deadline ran out, 404 when the source has no
+// data for the symbol, 502 for everything else the source did wrong.
+func upstreamStatus(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, client.ErrNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+// getQuoteHandler serves /api/quote/{symbol} and /api/quote/{symbol}/history.
+func (s *Server) getQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/quote/"), "/")
+	symbol := strings.ToUpper(parts[0])
+	if symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	if len(parts) > 1 {
+		switch sub := parts[1]; {
+		case sub == "history":
+			s.quoteHistory(w, r, symbol)
+		case sub == "intraday" && s.featureEnabled("intraday"):
+			s.intradayHandler(w, r, symbol)
+		case sub == "stats" && s.featureEnabled("stats"):
+			s.statsHandler(w, r, symbol)
+		default:
+			writeError(w, http.StatusNotFound, "unknown quote endpoint "+sub)
+		}
+		return
+	}
+
+	quote, err := s.fetchQuote(r, symbol)
+	if err != nil {
+		if stale := s.staleQuote(r, symbol); stale != nil {
+			reqLog(r.Context(), "WARNING: serving stale quote for %s, live fetch failed: %v", symbol, err)
+			writeJSON(w, http.StatusOK, staleQuoteResponse{
+				StockQuote: stale,
+				Stale:      true,
+				Age:        time.Since(stale.Timestamp).Round(time.Second).String(),
+			})
+			return
+		}
+		writeError(w, upstreamStatus(err), "failed to fetch quote: "+err.Error())
+		return
+	}
+	if err := s.checkFreshness(quote); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSONWithETag(w, r, quote)
+}
+
+// staleQuoteResponse is the shape of a fallback quote served after a
+// failed live fetch: the usual quote fields plus a stale marker and the
+// quote's age, so clients can tell the price is not current.
+type staleQuoteResponse struct {
+	*models.StockQuote
+	Stale bool   `json:"stale"`
+	Age   string `json:"age"`
+}
+
+// staleQuote returns the last stored quote for symbol if --stale-fallback
+// allows serving it: fallback enabled, storage reachable, a row present,
+// and the row no older than --stale-max-age (0 = any age). A nil return
+// means the caller should surface the fetch error as usual.
+func (s *Server) staleQuote(r *http.Request, symbol string) *models.StockQuote {
+	if !s.cfg.StaleFallback || s.storage == nil {
+		return nil
+	}
+	quote, err := s.storage.GetLatestQuote(r.Context(), symbol)
+	if err != nil {
+		return nil
+	}
+	if s.cfg.StaleMaxAge > 0 && time.Since(quote.Timestamp) > s.cfg.StaleMaxAge {
+		return nil
+	}
+	return quote
+}
+
+// checkFreshness rejects quotes older than the configured maximum age,
+// so stale prices error out instead of looking live. A zero MaxQuoteAge
+// disables the gate.
+func (s *Server) checkFreshness(quote *models.StockQuote) error {
+	if s.cfg.MaxQuoteAge <= 0 {
+		return nil
+	}
+	if age := time.Since(quote.Timestamp); age > s.cfg.MaxQuoteAge {
+		return fmt.Errorf("quote for %s is stale: age %s exceeds the maximum %s",
+			quote.Symbol, age.Round(time.Second), s.cfg.MaxQuoteAge)
+	}
+	return nil
+}
+
+// fetchQuote returns the freshest quote we can get for symbol: storage
+// first, upstream on a miss. A stored quote past the freshness gate
+// counts as a miss too, so the gate forces a refresh rather than a
+// guaranteed error. Upstream results are written back to storage.
+func (s *Server) fetchQuote(r *http.Request, symbol string) (*models.StockQuote, error) {
+	if s.storage != nil {
+		if quote, err := s.storage.GetLatestQuote(r.Context(), symbol); err == nil {
+			if s.checkFreshness(quote) == nil {
+				return quote, nil
+			}
+		}
+	}
+
+	ctx, span := trace.StartSpan(r.Context(), "upstream.GetStockQuote")
+	span.SetAttr("symbol", symbol)
+	quote, err := s.client.GetStockQuote(ctx, symbol)
+	span.RecordError(err)
+	span.End()
+	s.noteSourceHealth(r.Context(), s.upstreamSource(), err)
+	if err != nil {
+		return nil, err
+	}
+	s.storeQuote(r, quote)
+	return quote, nil
+}
+
+// shouldStore decides whether this request's fetches are written back
+// to the DB: the ?store= parameter when given, the --auto-store default
+// otherwise. Lets ad-hoc lookups opt out of bloating history.
+func (s *Server) shouldStore(r *http.Request) bool {
+	if v := r.URL.Query().Get("store"); v != "" {
+		if store, err := strconv.ParseBool(v); err == nil {
+			return store
+		}
+		reqLog(r.Context(), "WARNING: invalid store parameter %q, using default", v)
+	}
+	return s.cfg.AutoStore
+}
+
+// storeQuote validates an upstream quote and hands it to the background
+// writer; failures are logged there rather than failing any request.
+func (s *Server) storeQuote(r *http.Request, quote *models.StockQuote) {
+	if s.storage == nil || s.cfg.Mock || !s.shouldStore(r) {
+		return
+	}
+	source, err := models.NormalizeSource(quote.Source)
+	if err != nil {
+		reqLog(r.Context(), "WARNING: not storing quote for %s: %v", quote.Symbol, err)
+		return
+	}
+	quote.Source = string(source)
+	s.enqueueQuote(quote)
+}
+
+// quoteHistory serves the stored quote history for a symbol.
+func (s *Server) quoteHistory(w http.ResponseWriter, r *http.Request, symbol string) {
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "storage not available")
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	quotes, err := s.storage.GetQuoteHistory(r.Context(), symbol, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load history: "+err.Error())
+		return
+	}
+	if !s.cfg.AutoStore {
+		// With auto-store off, history only holds explicitly stored
+		// rows; make that visible to clients expecting full coverage.
+		w.Header().Set("X-Auto-Store", "off")
+	}
+	writeJSON(w, http.StatusOK, quotes)
+}
+
+// getIndexHandler serves /api/index/{symbol}.
+func (s *Server) getIndexHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	symbol := ResolveIndexSymbol(strings.TrimPrefix(r.URL.Path, "/api/index/"))
+	if symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	ctx, span := trace.StartSpan(r.Context(), "upstream.GetMarketIndex")
+	span.SetAttr("symbol", symbol)
+	index, err := s.client.GetMarketIndex(ctx, symbol)
+	span.RecordError(err)
+	span.End()
+	s.noteSourceHealth(r.Context(), s.upstreamSource(), err)
+	if err != nil {
+		writeError(w, upstreamStatus(err), "failed to fetch index: "+err.Error())
+		return
+	}
+	s.storeMarketData(r, index)
+	writeJSONWithETag(w, r, index)
+}
+
+// storeMarketData persists an upstream index value under its canonical
+// name, logging rather than failing the request if the write does not
+// succeed.
+func (s *Server) storeMarketData(r *http.Request, index *models.MarketIndex) {
+	if s.storage == nil || s.cfg.Mock || !s.shouldStore(r) {
+		return
+	}
+	ctx, span := trace.StartSpan(r.Context(), "db.InsertIndex")
+	span.SetAttr("symbol", index.Symbol)
+	err := s.storage.InsertIndex(ctx, index)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		reqLog(r.Context(), "WARNING: failed to store index %s: %v", index.Symbol, err)
+	}
+}
+
+// defaultMaxBatchBytes caps the payload of one batch response.
+const defaultMaxBatchBytes = 1 << 20 // 1 MiB
+
+// maxBatchSymbols caps how many symbols one batch request may ask for.
+const maxBatchSymbols = 50
+
+// batchResponse is the envelope for /api/quotes/batch. Truncated is set
+// when the size cap cut the result short; Omitted counts the symbols
+// left out. Errors maps each failed symbol to its fetch error.
+type batchResponse struct {
+	Quotes    []*models.StockQuote `json:"quotes"`
+	Errors    map[string]string    `json:"errors,omitempty"`
+	Truncated bool                 `json:"truncated"`
+	Omitted   int                  `json:"omitted,omitempty"`
+}
+
+// batchStatus picks the response status so clients can branch before
+// parsing: 200 when every symbol succeeded, 207 Multi-Status for a mix
+// of successes and failures, and 502 when every symbol failed (upstream
+// trouble, not a client mistake).
+func batchStatus(resp batchResponse) int {
+	switch {
+	case len(resp.Errors) == 0:
+		return http.StatusOK
+	case len(resp.Quotes) == 0:
+		return http.StatusBadGateway
+	}
+	return http.StatusMultiStatus
+}
+
+// maxBatchBytes returns the configured response-size cap for batch
+// endpoints; 0 means the built-in default, negative disables the cap.
+func (s *Server) maxBatchBytes() int {
+	if s.cfg.MaxBatchBytes == 0 {
+		return defaultMaxBatchBytes
+	}
+	return s.cfg.MaxBatchBytes
+}
+
+// batchQuotesHandler serves /api/quotes/batch in two shapes: POST with
+// a JSON body of {"symbols": [...]}, and GET with ?symbols=AAPL,MSFT
+// for simple clients and cacheability. Both return a quote per symbol,
+// truncating the result rather than exceeding the response-size cap.
+func (s *Server) batchQuotesHandler(w http.ResponseWriter, r *http.Request) {
+	symbols, ok := batchSymbolsFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	resp := s.fetchBatch(r, symbols)
+	if status := batchStatus(resp); status != http.StatusOK {
+		writeJSON(w, status, resp)
+		return
+	}
+	writeJSONConditional(w, r, batchETag(symbols, resp), resp)
+}
+
+// batchSymbolsFromRequest parses the symbol list a batch endpoint was
+// asked for, in either shape (POST body or GET query), enforcing the
+// presence and count limits. It writes the error response itself and
+// reports ok=false when the request was rejected.
+func batchSymbolsFromRequest(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	var symbols []string
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Symbols []string `json:"symbols"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return nil, false
+		}
+		symbols = req.Symbols
+	case http.MethodGet:
+		for _, symbol := range strings.Split(r.URL.Query().Get("symbols"), ",") {
+			if symbol = strings.TrimSpace(symbol); symbol != "" {
+				symbols = append(symbols, symbol)
+			}
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+
+	if len(symbols) == 0 {
+		writeError(w, http.StatusBadRequest, "symbols is required")
+		return nil, false
+	}
+	if len(symbols) > maxBatchSymbols {
+		writeError(w, http.StatusBadRequest,
+			fmt.Sprintf("too many symbols: %d (max %d)", len(symbols), maxBatchSymbols))
+		return nil, false
+	}
+	return symbols, true
+}
+
+// fetchBatch fetches quotes for the given symbols concurrently
+// (mirroring fetchIndices) and assembles the batch envelope, applying
+// the response-size cap in request order.
+func (s *Server) fetchBatch(r *http.Request, symbols []string) batchResponse {
+	fetched := make([]*models.StockQuote, len(symbols))
+	errs := make([]error, len(symbols))
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			// A panicking fetch becomes that symbol's error, not a dead
+			// process; the rest of the batch still resolves.
+			defer func() {
+				if v := recover(); v != nil {
+					reqLog(r.Context(), "ERROR: panic fetching %s: %v\n%s", symbol, v, debug.Stack())
+					errs[i] = fmt.Errorf("internal error fetching quote")
+				}
+			}()
+			quote, err := s.fetchQuote(r, strings.ToUpper(symbol))
+			if err != nil {
+				reqLog(r.Context(), "WARNING: batch fetch failed for %s: %v", symbol, err)
+				errs[i] = err
+				return
+			}
+			fetched[i] = quote
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	limit := s.maxBatchBytes()
+	resp := batchResponse{Quotes: make([]*models.StockQuote, 0, len(symbols))}
+	for i, err := range errs {
+		if err != nil {
+			if resp.Errors == nil {
+				resp.Errors = map[string]string{}
+			}
+			resp.Errors[strings.ToUpper(symbols[i])] = err.Error()
+		}
+	}
+	var size int
+	for i, quote := range fetched {
+		if quote == nil {
+			continue
+		}
+		if err := s.checkFreshness(quote); err != nil {
+			reqLog(r.Context(), "WARNING: dropping from batch: %v", err)
+			continue
+		}
+		if limit > 0 {
+			encoded, err := json.Marshal(quote)
+			if err != nil {
+				reqLog(r.Context(), "WARNING: encoding quote for %s: %v", symbols[i], err)
+				continue
+			}
+			if size+len(encoded) > limit && len(resp.Quotes) > 0 {
+				resp.Truncated = true
+				resp.Omitted = len(symbols) - i
+				reqLog(r.Context(), "WARNING: batch response truncated at %d of %d symbols (cap %d bytes)",
+					i, len(symbols), limit)
+				break
+			}
+			size += len(encoded)
+		}
+		resp.Quotes = append(resp.Quotes, quote)
+	}
+	return resp
+}
+
+// serviceStatus assembles the basic health summary served by /health
+// and embedded in the dashboard aggregate: component status plus the
+// recent latency distribution, which catches regressions a bare ok flag
+// misses. The percentile keys only appear once traffic has flowed.
+func (s *Server) serviceStatus() map[string]string {
+	status := map[string]string{"status": "ok", "version": version}
+	if s.storage == nil {
+		status["storage"] = "unavailable"
+	} else {
+		status["storage"] = "ok"
+	}
+	if s.metrics != nil {
+		if p50, p95, p99, ok := s.metrics.latencyPercentiles(); ok {
+			status["latency_p50"] = p50.Round(10 * time.Microsecond).String()
+			status["latency_p95"] = p95.Round(10 * time.Microsecond).String()
+			status["latency_p99"] = p99.Round(10 * time.Microsecond).String()
+		}
+	}
+	return status
+}
+
+// healthHandler reports basic service health.
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.serviceStatus())
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds each dependency probe so a hung dependency
+// can't stall the readiness answer past the orchestrator's patience.
+const readinessTimeout = 2 * time.Second
+
+// pinger is implemented by upstream clients that can be probed cheaply.
+// ProxyClient satisfies it; MockDataClient has nothing to probe.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// healthzHandler is the liveness probe: the process is up and serving,
+// so always 200. Dependency state belongs to /readyz.
+func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// dbStatsHandler reports connection-pool counters (open, idle, in-use
+// connections and wait counts) per database, for diagnosing pool
+// exhaustion during heavy ingest without attaching a profiler.
+func (s *Server) dbStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "database not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.storage.Stats())
+}
+
+// readyzHandler is the readiness probe: 503 with the list of failed
+// checks when a critical dependency (database, upstream client) is
+// unavailable, 200 otherwise.
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	failed := map[string]string{}
+	if s.storage != nil {
+		if err := s.storage.Ping(ctx); err != nil {
+			failed["database"] = err.Error()
+		}
+	}
+	if upstream, ok := s.client.(pinger); ok {
+		if err := upstream.Ping(ctx); err != nil {
+			failed["upstream"] = err.Error()
+		}
+	}
+
+	if len(failed) > 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status": "unavailable",
+			"failed": failed,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
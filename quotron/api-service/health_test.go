@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// failingUpstream is a DataClient whose probe always fails.
+type failingUpstream struct {
+	*MockDataClient
+}
+
+func (failingUpstream) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealthzAlways200(t *testing.T) {
+	s := &Server{} // no storage, no client: liveness doesn't care
+	rec := httptest.NewRecorder()
+	s.healthzHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestReadyzWithHealthyDependencies(t *testing.T) {
+	s := &Server{client: NewMockDataClient()} // mock has no probe, no DB configured
+	rec := httptest.NewRecorder()
+	s.readyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReadyzReportsFailedUpstream(t *testing.T) {
+	s := &Server{client: failingUpstream{NewMockDataClient()}}
+	rec := httptest.NewRecorder()
+	s.readyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	var resp struct {
+		Status string            `json:"status"`
+		Failed map[string]string `json:"failed"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if _, ok := resp.Failed["upstream"]; !ok {
+		t.Errorf("failed checks %v should include upstream", resp.Failed)
+	}
+}
+
+func TestDBStatsReportsPoolCounters(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{storage: NewStorage(db, ConflictIgnore)}
+	rec := httptest.NewRecorder()
+	s.dbStatsHandler(rec, httptest.NewRequest(http.MethodGet, "/health/db", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if _, ok := resp["primary"]; !ok {
+		t.Errorf("response %s should have primary pool stats", rec.Body.String())
+	}
+	if _, ok := resp["replica"]; ok {
+		t.Errorf("no replica configured, response %s should not report one", rec.Body.String())
+	}
+}
+
+func TestDBStatsWithoutStorageIs503(t *testing.T) {
+	s := &Server{}
+	rec := httptest.NewRecorder()
+	s.dbStatsHandler(rec, httptest.NewRequest(http.MethodGet, "/health/db", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
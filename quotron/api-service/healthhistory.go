@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Source status values recorded in health_history.
+const (
+	healthOK    = "ok"
+	healthError = "error"
+)
+
+// healthWatcher keeps the last observed status per source so only
+// transitions reach the database: the table grows with incidents, not
+// with request volume.
+type healthWatcher struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// note records an observation and reports whether it changed the
+// source's status.
+func (w *healthWatcher) note(source, status string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last == nil {
+		w.last = map[string]string{}
+	}
+	if w.last[source] == status {
+		return false
+	}
+	w.last[source] = status
+	return true
+}
+
+// upstreamSource is the health_history label for the configured data
+// client.
+func (s *Server) upstreamSource() string {
+	if s.cfg.DataClient != "" {
+		return s.cfg.DataClient
+	}
+	return "proxy"
+}
+
+// noteSourceHealth folds one fetch outcome into the source's health
+// timeline, appending a row when the status flipped. Recording failures
+// are logged, never surfaced: health bookkeeping must not break the
+// request that observed the outcome.
+func (s *Server) noteSourceHealth(ctx context.Context, source string, err error) {
+	if s.storage == nil || s.cfg.Mock {
+		return
+	}
+	status := healthOK
+	if err != nil {
+		status = healthError
+	}
+	if !s.health.note(source, status) {
+		return
+	}
+	if dbErr := s.storage.RecordHealthChange(ctx, source, status, time.Now()); dbErr != nil {
+		log.Printf("WARNING: recording health change for %s: %v", source, dbErr)
+	}
+}
+
+// defaultHealthWindow is how far back /health/history looks without a
+// since parameter.
+const defaultHealthWindow = 24 * time.Hour
+
+// healthHistoryResponse is the /health/history envelope. UptimePercent
+// is only present when a single source was asked for and its timeline
+// reaches into the window.
+type healthHistoryResponse struct {
+	Source        string         `json:"source,omitempty"`
+	Since         time.Time      `json:"since"`
+	Changes       []HealthChange `json:"changes"`
+	UptimePercent *float64       `json:"uptime_percent,omitempty"`
+}
+
+// healthHistoryHandler serves /health/history: the status timeline from
+// health_history, optionally filtered to one source (?source=), from a
+// given time onwards (?since=, RFC3339 or an age like 6h; default 24h).
+// With a source filter the response includes the uptime percentage over
+// the window, for SLA reporting.
+func (s *Server) healthHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "storage not available")
+		return
+	}
+
+	now := time.Now()
+	since := now.Add(-defaultHealthWindow)
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		since, err = parseSinceParam(v, now)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	source := r.URL.Query().Get("source")
+
+	changes, err := s.storage.HealthHistory(r.Context(), source, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load health history: "+err.Error())
+		return
+	}
+	if changes == nil {
+		changes = []HealthChange{}
+	}
+
+	resp := healthHistoryResponse{Source: source, Since: since, Changes: changes}
+	if source != "" {
+		prior, err := s.storage.LastHealthChangeBefore(r.Context(), source, since)
+		if err != nil && err != sql.ErrNoRows {
+			writeError(w, http.StatusInternalServerError, "failed to load health history: "+err.Error())
+			return
+		}
+		if uptime, ok := uptimePercent(prior, changes, since, now); ok {
+			resp.UptimePercent = &uptime
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseSinceParam accepts either an absolute RFC3339 time or an age
+// ("6h" means six hours before now).
+func parseSinceParam(v string, now time.Time) (time.Time, error) {
+	if age, err := time.ParseDuration(v); err == nil && age > 0 {
+		return now.Add(-age), nil
+	}
+	at, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q (want RFC3339 or an age like 6h)", v)
+	}
+	return at, nil
+}
+
+// uptimePercent computes the fraction of [from, to] a source spent in
+// the ok status, given its state entering the window (prior, nil when
+// unknown) and the changes inside it. When the state before the first
+// change is unknown, coverage starts at that change instead of from; a
+// timeline with no known state at all reports ok=false.
+func uptimePercent(prior *HealthChange, changes []HealthChange, from, to time.Time) (float64, bool) {
+	start := from
+	state := ""
+	if prior != nil {
+		state = prior.Status
+	} else if len(changes) > 0 {
+		start = changes[0].At
+	} else {
+		return 0, false
+	}
+	if !start.Before(to) {
+		return 0, false
+	}
+
+	var up time.Duration
+	cursor := start
+	for _, c := range changes {
+		at := c.At
+		if at.Before(cursor) {
+			at = cursor
+		}
+		if at.After(to) {
+			at = to
+		}
+		if state == healthOK {
+			up += at.Sub(cursor)
+		}
+		cursor, state = at, c.Status
+	}
+	if state == healthOK && to.After(cursor) {
+		up += to.Sub(cursor)
+	}
+	return 100 * float64(up) / float64(to.Sub(start)), true
+}
+
+// healthPruneInterval is how often the retention sweep runs.
+const healthPruneInterval = time.Hour
+
+// healthPruneLoop deletes health_history rows older than the configured
+// retention, once at startup and then every interval, so the timeline
+// stays bounded without an external cron.
+func (s *Server) healthPruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(healthPruneInterval)
+	defer ticker.Stop()
+	for {
+		cutoff := time.Now().Add(-s.cfg.HealthRetention)
+		if pruned, err := s.storage.PruneHealthHistory(ctx, cutoff); err != nil {
+			log.Printf("WARNING: pruning health history: %v", err)
+		} else if pruned > 0 {
+			log.Printf("pruned %d health history row(s) older than %s", pruned, s.cfg.HealthRetention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
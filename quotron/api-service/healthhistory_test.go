@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// healthDriver is a stub driver for the health timeline queries: the
+// window query gets a canned two-change timeline, the prior-state query
+// gets the status the window opened in.
+type healthDriver struct{}
+
+func (healthDriver) Open(name string) (driver.Conn, error) { return healthConn{}, nil }
+
+type healthConn struct{}
+
+func (healthConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (healthConn) Close() error              { return nil }
+func (healthConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (healthConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "changed_at < $2") {
+		return &healthRows{rows: [][]driver.Value{
+			{"proxy", "ok", time.Now().Add(-3 * time.Hour)},
+		}}, nil
+	}
+	return &healthRows{rows: [][]driver.Value{
+		{"proxy", "error", time.Now().Add(-40 * time.Minute)},
+		{"proxy", "ok", time.Now().Add(-20 * time.Minute)},
+	}}, nil
+}
+
+type healthRows struct {
+	rows   [][]driver.Value
+	served int
+}
+
+func (r *healthRows) Columns() []string { return []string{"source", "status", "changed_at"} }
+func (r *healthRows) Close() error      { return nil }
+func (r *healthRows) Next(dest []driver.Value) error {
+	if r.served >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.served])
+	r.served++
+	return nil
+}
+
+func init() {
+	sql.Register("healthtest", healthDriver{})
+}
+
+func healthServer(t *testing.T) *Server {
+	t.Helper()
+	db, err := sql.Open("healthtest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{cfg: Config{Mock: true}, storage: NewStorage(db, ConflictIgnore)}
+}
+
+func getHealthHistory(s *Server, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	s.healthHistoryHandler(rec, req)
+	return rec
+}
+
+func TestHealthHistoryServesTimelineAndUptime(t *testing.T) {
+	rec := getHealthHistory(healthServer(t), "/health/history?source=proxy&since=1h")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp healthHistoryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Changes) != 2 {
+		t.Fatalf("changes = %d, want the canned two", len(resp.Changes))
+	}
+	if resp.Changes[0].Status != "error" || resp.Changes[1].Status != "ok" {
+		t.Errorf("timeline = %+v, want error then recovery", resp.Changes)
+	}
+	if resp.UptimePercent == nil {
+		t.Fatal("a single-source window should report uptime")
+	}
+	// ok entering the window, down from -40m to -20m of a 1h window:
+	// two thirds up, give the clock a little slack.
+	if *resp.UptimePercent < 60 || *resp.UptimePercent > 72 {
+		t.Errorf("uptime = %.1f%%, want about 66.7%%", *resp.UptimePercent)
+	}
+}
+
+func TestHealthHistoryRejectsBadSince(t *testing.T) {
+	if rec := getHealthHistory(healthServer(t), "/health/history?since=yesterday"); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestHealthHistoryWithoutStorageIs503(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}}
+	if rec := getHealthHistory(s, "/health/history"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestUptimePercent(t *testing.T) {
+	from := time.Date(2026, 8, 27, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	prior := func(status string) *HealthChange {
+		return &HealthChange{Source: "proxy", Status: status, At: from.Add(-time.Hour)}
+	}
+	change := func(status string, offset time.Duration) HealthChange {
+		return HealthChange{Source: "proxy", Status: status, At: from.Add(offset)}
+	}
+
+	cases := []struct {
+		name    string
+		prior   *HealthChange
+		changes []HealthChange
+		want    float64
+		ok      bool
+	}{
+		{"steady ok", prior("ok"), nil, 100, true},
+		{"steady down", prior("error"), nil, 0, true},
+		{"down for a quarter", prior("ok"),
+			[]HealthChange{change("error", 30*time.Minute), change("ok", 45*time.Minute)}, 75, true},
+		{"unknown before first change", nil,
+			[]HealthChange{change("ok", 30*time.Minute)}, 100, true},
+		{"no data at all", nil, nil, 0, false},
+	}
+	for _, c := range cases {
+		got, ok := uptimePercent(c.prior, c.changes, from, to)
+		if ok != c.ok {
+			t.Errorf("%s: ok = %v, want %v", c.name, ok, c.ok)
+			continue
+		}
+		if ok && (got < c.want-0.01 || got > c.want+0.01) {
+			t.Errorf("%s: uptime = %.2f%%, want %.2f%%", c.name, got, c.want)
+		}
+	}
+}
+
+func TestHealthWatcherRecordsOnlyTransitions(t *testing.T) {
+	var w healthWatcher
+	seq := []struct {
+		status string
+		change bool
+	}{
+		{"ok", true},
+		{"ok", false},
+		{"error", true},
+		{"error", false},
+		{"ok", true},
+	}
+	for i, step := range seq {
+		if got := w.note("proxy", step.status); got != step.change {
+			t.Errorf("step %d (%s): change = %v, want %v", i, step.status, got, step.change)
+		}
+	}
+	if !w.note("yahoo", "ok") {
+		t.Error("a new source's first observation is a transition")
+	}
+}
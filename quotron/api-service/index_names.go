@@ -0,0 +1,11 @@
+package main
+
+import "github.com/we-be/tiny-ria/quotron/models"
+
+// ResolveIndexSymbol translates a friendly index name to its upstream
+// symbol via the shared alias table in models, so the API and agent
+// agree on what "Dow Jones" means.
+func ResolveIndexSymbol(name string) string {
+	symbol, _ := models.ResolveIndex(name)
+	return symbol
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestResolveIndexSymbol(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"S&P 500", "^GSPC"},
+		{"s&p  500", "^GSPC"},
+		{"Dow Jones", "^DJI"},
+		{"NASDAQ", "^IXIC"},
+		{"Russell 2000", "^RUT"},
+		{"^GSPC", "^GSPC"},
+		{"^gspc", "^GSPC"},
+		{"FTSE 100", "FTSE 100"}, // unknown names pass through upcased
+	}
+	for _, tt := range tests {
+		if got := ResolveIndexSymbol(tt.name); got != tt.want {
+			t.Errorf("ResolveIndexSymbol(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
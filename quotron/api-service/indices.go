@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// defaultIndexList is the curated set served by /api/indices when
+// --default-indices is not given: the major US market indices the
+// dashboard shows.
+const defaultIndexList = "^GSPC,^DJI,^IXIC,^RUT"
+
+// indicesHandler serves GET /api/indices: the configured default index
+// set in one response, fetched concurrently upstream.
+func (s *Server) indicesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	indices, _ := s.fetchIndices(r, s.cfg.DefaultIndices)
+	writeJSONWithETag(w, r, indices)
+}
+
+// fetchIndices fetches the named indices concurrently, preserving the
+// requested order. Failures are logged and returned keyed by the
+// requested name, so callers can surface them per item. Names may be
+// friendly aliases; they are resolved to upstream symbols first.
+// Successful fetches are written back to storage under the request's
+// store policy, same as the quote paths — the scheduled market_indices
+// job relies on this to persist what it collects.
+func (s *Server) fetchIndices(r *http.Request, names []string) ([]*models.MarketIndex, map[string]string) {
+	results := make([]*models.MarketIndex, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() {
+				if v := recover(); v != nil {
+					reqLog(r.Context(), "ERROR: panic fetching index %s: %v\n%s", name, v, debug.Stack())
+					errs[i] = fmt.Errorf("internal error fetching index")
+				}
+			}()
+			index, err := s.client.GetMarketIndex(r.Context(), ResolveIndexSymbol(name))
+			if err != nil {
+				reqLog(r.Context(), "WARNING: fetching index %s: %v", name, err)
+				errs[i] = err
+				return
+			}
+			s.storeMarketData(r, index)
+			results[i] = index
+		}(i, name)
+	}
+	wg.Wait()
+
+	indices := make([]*models.MarketIndex, 0, len(names))
+	var failed map[string]string
+	for i, index := range results {
+		if errs[i] != nil {
+			if failed == nil {
+				failed = map[string]string{}
+			}
+			failed[names[i]] = errs[i].Error()
+			continue
+		}
+		indices = append(indices, index)
+	}
+	return indices, failed
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func TestIndicesReturnsDefaultSetInOrder(t *testing.T) {
+	s := &Server{
+		cfg: Config{
+			Mock:           true,
+			DefaultIndices: []string{"^GSPC", "^DJI", "^IXIC", "^RUT"},
+		},
+		client: NewMockDataClient(),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/indices", nil)
+	rec := httptest.NewRecorder()
+	s.indicesHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var indices []*models.MarketIndex
+	if err := json.Unmarshal(rec.Body.Bytes(), &indices); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(indices) != 4 {
+		t.Fatalf("indices = %d, want 4", len(indices))
+	}
+	for i, want := range []string{"^GSPC", "^DJI", "^IXIC", "^RUT"} {
+		if indices[i].Symbol != want {
+			t.Errorf("indices[%d] = %s, want %s", i, indices[i].Symbol, want)
+		}
+	}
+}
+
+func TestIndicesStoresFetchedValues(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		cfg:     Config{DefaultIndices: []string{"^GSPC", "^DJI"}},
+		storage: NewStorage(db, ConflictIgnore),
+		client:  NewMockDataClient(),
+	}
+
+	// The scheduled market_indices job GETs exactly this.
+	req := httptest.NewRequest(http.MethodGet, "/api/indices?store=true", nil)
+	rec := httptest.NewRecorder()
+	s.indicesHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if got := queryCount("primary"); got != 2 {
+		t.Errorf("index inserts = %d, want one per fetched index", got)
+	}
+
+	resetRouteLog()
+	s.indicesHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/indices?store=false", nil))
+	if got := queryCount("primary"); got != 0 {
+		t.Errorf("inserts with ?store=false = %d, want 0", got)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" ^GSPC, ,^DJI,")
+	if len(got) != 2 || got[0] != "^GSPC" || got[1] != "^DJI" {
+		t.Errorf("splitList = %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Supported intraday bucket widths.
+var intradayIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+}
+
+// maxIntradayBuckets caps how many buckets one request may ask for, so
+// combinations like 1m over 1y are rejected instead of scanning a year
+// of rows.
+const maxIntradayBuckets = 2000
+
+// parseIntradayRange accepts the compact range syntax used by the chart
+// frontend: 1h, 4h, 1d, 5d, 1w.
+func parseIntradayRange(s string) (time.Duration, error) {
+	switch s {
+	case "1h":
+		return time.Hour, nil
+	case "4h":
+		return 4 * time.Hour, nil
+	case "1d", "":
+		return 24 * time.Hour, nil
+	case "5d":
+		return 5 * 24 * time.Hour, nil
+	case "1w":
+		return 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("unsupported range %q (want 1h, 4h, 1d, 5d, or 1w)", s)
+}
+
+// intradayHandler serves /api/quote/{symbol}/intraday?interval=5m&range=1d
+// with OHLC buckets aggregated from stored quotes, falling back to the
+// upstream series when storage has nothing for the window.
+func (s *Server) intradayHandler(w http.ResponseWriter, r *http.Request, symbol string) {
+	intervalArg := r.URL.Query().Get("interval")
+	if intervalArg == "" {
+		intervalArg = "5m"
+	}
+	interval, ok := intradayIntervals[intervalArg]
+	if !ok {
+		writeError(w, http.StatusBadRequest,
+			fmt.Sprintf("unsupported interval %q (want 1m, 5m, 15m, or 1h)", intervalArg))
+		return
+	}
+
+	rangeArg := r.URL.Query().Get("range")
+	window, err := parseIntradayRange(rangeArg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if int(window/interval) > maxIntradayBuckets {
+		writeError(w, http.StatusBadRequest,
+			fmt.Sprintf("interval %s over range %s needs %d buckets (max %d)",
+				intervalArg, rangeArg, window/interval, maxIntradayBuckets))
+		return
+	}
+
+	since := time.Now().UTC().Add(-window)
+	if s.storage != nil {
+		buckets, err := s.storage.GetIntradayBuckets(r.Context(), symbol, interval, since)
+		if err == nil && len(buckets) > 0 {
+			writeJSONWithETag(w, r, buckets)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to aggregate intraday data: "+err.Error())
+			return
+		}
+	}
+
+	// Storage empty (or absent): let the upstream proxy build the series.
+	buckets, err := s.client.GetIntradaySeries(r.Context(), symbol, intervalArg, rangeArg)
+	if err != nil {
+		writeError(w, upstreamStatus(err), "no intraday data available: "+err.Error())
+		return
+	}
+	writeJSONWithETag(w, r, buckets)
+}
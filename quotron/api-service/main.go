@@ -0,0 +1,142 @@
+// api-service serves stock quotes and market index data over HTTP.
+//
+// Quotes are read from Postgres when available and fetched from the
+// upstream proxy on a cache miss. The service also hosts the dashboard.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/db"
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// splitList parses a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// defaultDBURL prefers an explicit DATABASE_URL, then the discrete DB_*
+// variables via the shared DSN builder.
+func defaultDBURL() string {
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		return v
+	}
+	return db.BuildDSNFromEnv()
+}
+
+func main() {
+	port := flag.Int("port", 8080, "port to listen on")
+	dbURL := flag.String("db-url", defaultDBURL(), "Postgres connection string")
+	replicaURL := flag.String("replica-url", os.Getenv("DATABASE_REPLICA_URL"), "optional read-replica connection string")
+	proxyURL := flag.String("proxy-url", "http://localhost:8001", "upstream quote proxy base URL")
+	dataClient := flag.String("data-client", "proxy", "registered upstream data source to use")
+	noCompression := flag.Bool("no-compression", false, "disable gzip response compression")
+	mock := flag.Bool("mock", false, "serve deterministic mock data (offline developer mode, disables DB writes)")
+	demo := flag.Bool("demo", false, "self-contained demo: synthetic data, no database, no external dependencies")
+	strictSources := flag.Bool("strict-sources", false, "reject quotes whose source is not in the data_source enum")
+	defaultExchange := flag.String("default-exchange", string(models.ExchangeOther), "exchange bucket for venues not in the exchange enum")
+	conflictPolicy := flag.String("conflict-policy", "ignore", "quote insert conflict policy: error, ignore, or update")
+	queryTimeout := flag.Duration("query-timeout", defaultQueryTimeout, "per-query database timeout (negative = unlimited)")
+	slowQuery := flag.Duration("slow-query-threshold", defaultSlowQueryThreshold, "log queries slower than this (negative = disabled)")
+	maxBatchBytes := flag.Int("max-batch-bytes", defaultMaxBatchBytes, "response-size cap for batch endpoints (negative = unlimited)")
+	defaultIndices := flag.String("default-indices", defaultIndexList, "comma-separated index set served by /api/indices")
+	defaultSymbols := flag.String("default-symbols", defaultSymbolList, "comma-separated equity set the dashboard polls")
+	defaultCryptos := flag.String("default-cryptos", defaultCryptoList, "comma-separated coin set served by /api/dashboard")
+	dashboardRefresh := flag.Duration("dashboard-refresh", defaultDashboardRefresh, "dashboard auto-refresh interval (minimum 5s)")
+	maxQuoteAge := flag.Duration("max-quote-age", 0, "reject quotes older than this instead of serving them (0 = disabled)")
+	staleFallback := flag.Bool("stale-fallback", false, "serve the last stored quote, marked stale, when the live fetch fails")
+	staleMaxAge := flag.Duration("stale-max-age", time.Hour, "oldest stored quote -stale-fallback may serve (0 = any age)")
+	healthRetention := flag.Duration("health-retention", 0, "prune health history rows older than this (0 = keep everything)")
+	disableFeatures := flag.String("disable-features", "", "comma-separated experimental features to turn off (correlation, intraday, stats)")
+	autoStore := flag.Bool("auto-store", true, "write fetched quotes and indices back to the DB (per-request override: ?store=)")
+	traceEndpoint := flag.String("trace-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "collector URL for trace spans (empty = no export)")
+	storeBackend := flag.String("store", "postgres", "storage backend (postgres; sqlite reserved, pending a pure-Go driver)")
+	storePath := flag.String("store-path", "", "database file for file-backed store backends")
+	webhookSecrets := flag.String("webhook-secrets", os.Getenv("WEBHOOK_SECRETS"), "comma-separated source=secret pairs for push ingest")
+	webhookMappings := flag.String("webhook-mappings", "", "JSON file of declarative payload mappings per webhook source")
+	cryptoQuote := flag.String("crypto-quote", defaultCryptoQuote, "quote currency for bare crypto symbols (per-request override: ?quote=)")
+	flag.Parse()
+
+	models.StrictSources = *strictSources
+
+	exchange, ok := models.MapExchangeToEnumStrict(*defaultExchange)
+	if !ok {
+		log.Fatalf("invalid -default-exchange %q (want one of %v)", *defaultExchange, models.AllExchanges())
+	}
+	models.DefaultExchange = exchange
+
+	policy, err := ParseConflictPolicy(*conflictPolicy)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := validateDashboardRefresh(*dashboardRefresh); err != nil {
+		log.Fatal(err)
+	}
+	secrets, err := parseWebhookSecrets(*webhookSecrets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *webhookMappings != "" {
+		if err := loadWebhookMappings(*webhookMappings); err != nil {
+			log.Fatal(err)
+		}
+	}
+	quoteCurrency := strings.ToUpper(*cryptoQuote)
+	if !validQuoteCurrency(quoteCurrency) {
+		log.Fatalf("invalid -crypto-quote %q (want a currency code like USD or EUR)", *cryptoQuote)
+	}
+
+	cfg := Config{
+		Port:             *port,
+		DatabaseURL:      *dbURL,
+		ReplicaURL:       *replicaURL,
+		ProxyURL:         *proxyURL,
+		DataClient:       *dataClient,
+		NoCompression:    *noCompression,
+		Mock:             *mock,
+		ConflictPolicy:   policy,
+		QueryTimeout:     *queryTimeout,
+		SlowQuery:        *slowQuery,
+		MaxBatchBytes:    *maxBatchBytes,
+		DefaultIndices:   splitList(*defaultIndices),
+		DefaultSymbols:   splitList(*defaultSymbols),
+		DefaultCryptos:   splitList(*defaultCryptos),
+		DashboardRefresh: *dashboardRefresh,
+		MaxQuoteAge:      *maxQuoteAge,
+		StaleFallback:    *staleFallback,
+		StaleMaxAge:      *staleMaxAge,
+		HealthRetention:  *healthRetention,
+		DisabledFeatures: splitList(*disableFeatures),
+		AutoStore:        *autoStore,
+		TraceEndpoint:    *traceEndpoint,
+		StoreBackend:     *storeBackend,
+		StorePath:        *storePath,
+		WebhookSecrets:   secrets,
+		CryptoQuote:      quoteCurrency,
+	}
+
+	if *demo {
+		applyDemoMode(&cfg)
+		log.Printf("demo mode: serving synthetic data with no external dependencies")
+	}
+
+	server, err := NewServer(cfg)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
+
+	if err := server.Start(); err != nil {
+		log.Fatalf("server exited: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsRegistry accumulates per-route request counts, 5xx counts, and
+// latency for the /metrics endpoint. Routes are labeled by their mux
+// pattern, not the raw path, so symbols never explode the label set.
+type metricsRegistry struct {
+	mu     sync.Mutex
+	routes map[string]*routeStats
+}
+
+type routeStats struct {
+	requests int64
+	errors   int64
+	elapsed  time.Duration
+	// samples is a ring of recent durations for the percentile
+	// computation; next is where the ring overwrites once full.
+	samples []time.Duration
+	next    int
+}
+
+// maxLatencySamples bounds the per-route duration ring: enough for
+// stable percentiles, small enough to never matter for memory.
+const maxLatencySamples = 512
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{routes: map[string]*routeStats{}}
+}
+
+// instrument wraps a handler so every request is recorded under the
+// route pattern it was registered with.
+func (m *metricsRegistry) instrument(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next(sw, r)
+		m.record(route, sw.status, time.Since(start))
+	}
+}
+
+func (m *metricsRegistry) record(route string, status int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.routes[route]
+	if stats == nil {
+		stats = &routeStats{}
+		m.routes[route] = stats
+	}
+	stats.requests++
+	if status >= 500 {
+		stats.errors++
+	}
+	stats.elapsed += elapsed
+	if len(stats.samples) < maxLatencySamples {
+		stats.samples = append(stats.samples, elapsed)
+	} else {
+		stats.samples[stats.next] = elapsed
+		stats.next = (stats.next + 1) % maxLatencySamples
+	}
+}
+
+// latencyPercentiles computes the p50/p95/p99 request durations across
+// every route's recent samples; ok is false before any traffic.
+func (m *metricsRegistry) latencyPercentiles() (p50, p95, p99 time.Duration, ok bool) {
+	m.mu.Lock()
+	var all []time.Duration
+	for _, stats := range m.routes {
+		all = append(all, stats.samples...)
+	}
+	m.mu.Unlock()
+	if len(all) == 0 {
+		return 0, 0, 0, false
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return percentile(all, 50), percentile(all, 95), percentile(all, 99), true
+}
+
+// percentile picks the nearest-rank percentile from sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// statusWriter records the response status for the registry. Upgrades
+// (the WebSocket stream) pass Hijack through untouched.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// metricsHandler serves /metrics in the Prometheus text format, so the
+// standard monitoring stack can scrape the service instead of polling
+// the JSON health endpoints: per-route traffic and error counters, mean
+// latency gauges, and up/down gauges for the service and its database.
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		s.metrics = newMetricsRegistry()
+	}
+	var b strings.Builder
+
+	b.WriteString("# HELP quotron_up Whether the API service is serving.\n")
+	b.WriteString("# TYPE quotron_up gauge\n")
+	b.WriteString("quotron_up 1\n")
+
+	b.WriteString("# HELP quotron_storage_up Whether the database is configured and reachable.\n")
+	b.WriteString("# TYPE quotron_storage_up gauge\n")
+	fmt.Fprintf(&b, "quotron_storage_up %d\n", boolGauge(s.storage != nil))
+
+	s.metrics.mu.Lock()
+	routes := make([]string, 0, len(s.metrics.routes))
+	for route := range s.metrics.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	b.WriteString("# HELP quotron_requests_total Requests served, by route.\n")
+	b.WriteString("# TYPE quotron_requests_total counter\n")
+	for _, route := range routes {
+		fmt.Fprintf(&b, "quotron_requests_total{route=%q} %d\n", route, s.metrics.routes[route].requests)
+	}
+
+	b.WriteString("# HELP quotron_request_errors_total Responses with a 5xx status, by route.\n")
+	b.WriteString("# TYPE quotron_request_errors_total counter\n")
+	for _, route := range routes {
+		fmt.Fprintf(&b, "quotron_request_errors_total{route=%q} %d\n", route, s.metrics.routes[route].errors)
+	}
+
+	b.WriteString("# HELP quotron_request_duration_seconds Mean request duration, by route.\n")
+	b.WriteString("# TYPE quotron_request_duration_seconds gauge\n")
+	for _, route := range routes {
+		stats := s.metrics.routes[route]
+		mean := stats.elapsed.Seconds() / float64(stats.requests)
+		fmt.Fprintf(&b, "quotron_request_duration_seconds{route=%q} %g\n", route, mean)
+	}
+	s.metrics.mu.Unlock()
+
+	if s.storage != nil {
+		b.WriteString("# HELP quotron_db_connections Connection-pool counters per database.\n")
+		b.WriteString("# TYPE quotron_db_connections gauge\n")
+		stats := s.storage.Stats()
+		dbs := make([]string, 0, len(stats))
+		for name := range stats {
+			dbs = append(dbs, name)
+		}
+		sort.Strings(dbs)
+		for _, name := range dbs {
+			pool := stats[name]
+			fmt.Fprintf(&b, "quotron_db_connections{db=%q,state=\"open\"} %d\n", name, pool.OpenConnections)
+			fmt.Fprintf(&b, "quotron_db_connections{db=%q,state=\"in_use\"} %d\n", name, pool.InUse)
+			fmt.Fprintf(&b, "quotron_db_connections{db=%q,state=\"idle\"} %d\n", name, pool.Idle)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func boolGauge(up bool) int {
+	if up {
+		return 1
+	}
+	return 0
+}
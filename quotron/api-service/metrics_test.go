@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsCountRequestsByRoutePattern(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient()}
+	mux := s.setupRoutes()
+
+	// Two different symbols must land under one route label.
+	for _, target := range []string{"/api/quote/AAPL", "/api/quote/MSFT", "/health"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", target, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("metrics status = %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"quotron_up 1",
+		"quotron_storage_up 0",
+		`quotron_requests_total{route="/api/quote/"} 2`,
+		`quotron_requests_total{route="/health"} 1`,
+		`quotron_request_duration_seconds{route="/api/quote/"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+	if !strings.Contains(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestMetricsCountServerErrors(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: erroringClient{err: errors.New("proxy is down")}}
+	mux := s.setupRoutes()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `quotron_request_errors_total{route="/api/quote/"} 1`) {
+		t.Errorf("metrics should count the 502 as a route error:\n%s", rec.Body.String())
+	}
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	m := newMetricsRegistry()
+	// 1ms..100ms: the nearest-rank percentiles are exactly 50, 95, 99.
+	for i := 1; i <= 100; i++ {
+		m.record("/api/quote/", http.StatusOK, time.Duration(i)*time.Millisecond)
+	}
+
+	p50, p95, p99, ok := m.latencyPercentiles()
+	if !ok {
+		t.Fatal("percentiles should be available after traffic")
+	}
+	if p50 != 50*time.Millisecond || p95 != 95*time.Millisecond || p99 != 99*time.Millisecond {
+		t.Errorf("percentiles = %v/%v/%v, want 50ms/95ms/99ms", p50, p95, p99)
+	}
+
+	if _, _, _, ok := newMetricsRegistry().latencyPercentiles(); ok {
+		t.Error("an idle registry has no percentiles to report")
+	}
+}
+
+func TestHealthSummaryIncludesLatencyPercentiles(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient()}
+	mux := s.setupRoutes()
+
+	status := s.serviceStatus()
+	if _, ok := status["latency_p50"]; ok {
+		t.Error("summary reported latency before any traffic")
+	}
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d", rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"latency_p50", "latency_p95", "latency_p99"} {
+		v, ok := got[key]
+		if !ok || v == "" {
+			t.Errorf("summary missing %s: %v", key, got)
+			continue
+		}
+		if _, err := time.ParseDuration(v); err != nil {
+			t.Errorf("%s = %q, want a duration string", key, v)
+		}
+	}
+}
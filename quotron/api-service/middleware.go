@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// corsMiddleware allows the dashboard (and other browser clients) to call
+// the API from any origin.
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recoverMiddleware turns a handler panic into a logged 500 instead of
+// a dead process: one bad request must not take the service down for
+// everyone else. The stack trace goes to the log under the request ID.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				reqLog(r.Context(), "ERROR: panic serving %s %s: %v\n%s",
+					r.Method, r.URL.Path, v, debug.Stack())
+				writeError(w, http.StatusInternalServerError, "internal server error")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// guard runs fn, turning a panic into a log line. recoverMiddleware
+// covers request handlers; this is the same protection for background
+// goroutines, where an unrecovered panic takes the whole process down
+// (or silently kills the only worker).
+func guard(name string, fn func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("ERROR: panic in %s: %v\n%s", name, v, debug.Stack())
+		}
+	}()
+	fn()
+}
+
+// gzipMinSize is the smallest response body we bother compressing.
+// Below this the gzip framing overhead outweighs the savings.
+const gzipMinSize = 1024
+
+// gzipMiddleware compresses response bodies for clients that send
+// Accept-Encoding: gzip. Responses smaller than gzipMinSize are passed
+// through unmodified.
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The cache key depends on Accept-Encoding either way.
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// gzipResponseWriter buffers the first gzipMinSize bytes of the body so
+// small responses skip compression entirely. Once the threshold is
+// crossed it switches to a streaming gzip writer.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buf         []byte
+	gz          *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	// Defer the real WriteHeader until we know whether we compress,
+	// because Content-Encoding must be set first.
+	w.status = status
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= gzipMinSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// startGzip commits to compressed output and flushes the buffer through gzip.
+func (w *gzipResponseWriter) startGzip() error {
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.wroteHeader = true
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// Close flushes whatever path we ended up on: the gzip stream, or the
+// plain buffered body for responses under the threshold.
+func (w *gzipResponseWriter) Close() error {
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	if !w.wroteHeader {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) > 0 {
+		_, err := w.ResponseWriter.Write(w.buf)
+		return err
+	}
+	return nil
+}
+
+// Hijack passes WebSocket upgrades and similar through to the underlying
+// connection uncompressed.
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverMiddlewareKeepsServing(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	calls := 0
+	handler := recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("nil map write")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("panicking request status = %d, want 500", rec.Code)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "panic serving GET /api/quote/AAPL: nil map write") {
+		t.Errorf("log output %q should name the panic and the request", out)
+	}
+	if !strings.Contains(out, "middleware_test.go") {
+		t.Errorf("log output %q should carry a stack trace", out)
+	}
+
+	// The process (and the handler) survive: the next request succeeds.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("follow-up request status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRecoverMiddlewarePassesThroughNormally(t *testing.T) {
+	handler := recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want the handler's 418", rec.Code)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// MockDataClient is the --mock developer mode: a DataClient producing
+// deterministic but plausible data so the whole service (and the
+// dashboard against it) runs offline, without API keys or the proxy.
+// Prices follow a per-symbol random walk seeded from the symbol name, so
+// the same symbol always starts at the same price.
+type MockDataClient struct {
+	mu    sync.Mutex
+	walks map[string]*symbolWalk
+}
+
+type symbolWalk struct {
+	rng       *rand.Rand
+	price     float64
+	prevClose float64
+}
+
+// NewMockDataClient builds the mock client.
+func NewMockDataClient() *MockDataClient {
+	return &MockDataClient{walks: map[string]*symbolWalk{}}
+}
+
+// walk returns (creating if needed) the random walk for a symbol.
+func (m *MockDataClient) walk(symbol string) *symbolWalk {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.walks[symbol]
+	if !ok {
+		h := fnv.New64a()
+		h.Write([]byte(symbol))
+		seed := int64(h.Sum64())
+		rng := rand.New(rand.NewSource(seed))
+		start := 10 + rng.Float64()*490 // plausible equity price range
+		w = &symbolWalk{rng: rng, price: start, prevClose: start}
+		m.walks[symbol] = w
+	}
+	return w
+}
+
+// step advances a symbol's walk by one tick and returns the new price.
+func (m *MockDataClient) step(symbol string) (price, prevClose float64) {
+	w := m.walk(symbol)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.price += w.price * (w.rng.Float64() - 0.5) * 0.01
+	return w.price, w.prevClose
+}
+
+// GetStockQuote returns the next tick of the symbol's random walk.
+func (m *MockDataClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	price, prevClose := m.step(symbol)
+	quote := &models.StockQuote{
+		Symbol:        symbol,
+		Price:         price,
+		Change:        price - prevClose,
+		ChangePercent: (price - prevClose) / prevClose * 100,
+		Volume:        models.Int64(int64(1_000_000 + m.walk(symbol).rng.Intn(9_000_000))),
+		Timestamp:     time.Now().UTC(),
+		Exchange:      "MOCK",
+		Source:        "mock",
+	}
+	// Crypto pairs carry 24h extremes, like the real upstream does.
+	if strings.Contains(symbol, "-") {
+		quote.High24h = models.Float64(price * 1.05)
+		quote.Low24h = models.Float64(price * 0.95)
+	}
+	return quote, nil
+}
+
+// GetMarketIndex returns a mock index value on the same walk machinery.
+func (m *MockDataClient) GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value, prevClose := m.step("index:" + symbol)
+	return &models.MarketIndex{
+		Name:      symbol,
+		Symbol:    symbol,
+		Value:     value * 10, // indices sit an order of magnitude above equities
+		Change:    (value - prevClose) * 10,
+		Timestamp: time.Now().UTC(),
+		Source:    "mock",
+	}, nil
+}
+
+// GetIntradaySeries synthesizes a bucket series by running the walk
+// backwards from the current price.
+func (m *MockDataClient) GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	width, ok := intradayIntervals[interval]
+	if !ok {
+		width = 5 * time.Minute
+	}
+	window, err := parseIntradayRange(dataRange)
+	if err != nil {
+		window = 24 * time.Hour
+	}
+
+	n := int(window / width)
+	if n > maxIntradayBuckets {
+		n = maxIntradayBuckets
+	}
+	price, _ := m.step(symbol)
+	w := m.walk(symbol)
+
+	start := time.Now().UTC().Add(-window).Truncate(width)
+	buckets := make([]models.OHLCBucket, 0, n)
+	for i := 0; i < n; i++ {
+		m.mu.Lock()
+		open := price
+		high := open * (1 + w.rng.Float64()*0.005)
+		low := open * (1 - w.rng.Float64()*0.005)
+		close := low + (high-low)*w.rng.Float64()
+		volume := int64(100_000 + w.rng.Intn(900_000))
+		m.mu.Unlock()
+		buckets = append(buckets, models.OHLCBucket{
+			Start:  start.Add(time.Duration(i) * width),
+			Open:   open,
+			High:   high,
+			Low:    low,
+			Close:  close,
+			Volume: volume,
+		})
+		price = close
+	}
+	return buckets, nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// pgListener is a minimal Postgres frontend speaking just enough of the
+// wire protocol to LISTEN on one channel and receive notifications.
+// database/sql cannot surface async NotificationResponse messages, and we
+// deliberately avoid a driver dependency for this one feature — the
+// bridge needs startup, password auth, one LISTEN, and the notification
+// stream, and nothing else. TLS connections are not supported; the
+// bridge falls back gracefully when it cannot connect.
+type pgListener struct {
+	conn          net.Conn
+	r             *bufio.Reader
+	notifications chan string
+}
+
+// dialPGListener connects with the given postgres:// DSN, authenticates,
+// issues LISTEN on the channel, and starts delivering notification
+// payloads on Notifications.
+func dialPGListener(dsn, channel string) (*pgListener, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing DSN: %w", err)
+	}
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		host += ":5432"
+	}
+	user := u.User.Username()
+	pass, _ := u.User.Password()
+	dbname := strings.TrimPrefix(u.Path, "/")
+
+	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	l := &pgListener{
+		conn:          conn,
+		r:             bufio.NewReader(conn),
+		notifications: make(chan string, 64),
+	}
+	if err := l.startup(user, pass, dbname); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := l.query("LISTEN " + channel); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go l.receive()
+	return l, nil
+}
+
+// Notifications delivers payloads as they arrive. The channel is closed
+// when the connection drops.
+func (l *pgListener) Notifications() <-chan string { return l.notifications }
+
+// Close drops the connection; the receive loop then closes Notifications.
+func (l *pgListener) Close() error { return l.conn.Close() }
+
+// startup performs the startup handshake, answering a cleartext or MD5
+// password request, and waits for ReadyForQuery.
+func (l *pgListener) startup(user, pass, dbname string) error {
+	params := "user\x00" + user + "\x00database\x00" + dbname + "\x00\x00"
+	buf := make([]byte, 8, 8+len(params))
+	binary.BigEndian.PutUint32(buf, uint32(8+len(params)))
+	binary.BigEndian.PutUint32(buf[4:], 196608) // protocol 3.0
+	if _, err := l.conn.Write(append(buf, params...)); err != nil {
+		return err
+	}
+
+	for {
+		typ, payload, err := l.readMessage()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'R':
+			if err := l.answerAuth(payload, user, pass); err != nil {
+				return err
+			}
+		case 'E':
+			return fmt.Errorf("postgres: %s", errorMessage(payload))
+		case 'Z':
+			return nil // ReadyForQuery
+		}
+		// Ignore ParameterStatus, BackendKeyData, notices.
+	}
+}
+
+// answerAuth responds to an authentication request. Code 0 is OK, 3 is
+// cleartext password, 5 is MD5.
+func (l *pgListener) answerAuth(payload []byte, user, pass string) error {
+	if len(payload) < 4 {
+		return fmt.Errorf("short authentication message")
+	}
+	switch code := binary.BigEndian.Uint32(payload); code {
+	case 0:
+		return nil
+	case 3:
+		return l.writeMessage('p', []byte(pass+"\x00"))
+	case 5:
+		if len(payload) < 8 {
+			return fmt.Errorf("MD5 authentication without salt")
+		}
+		inner := md5Hex([]byte(pass + user))
+		outer := md5Hex(append([]byte(inner), payload[4:8]...))
+		return l.writeMessage('p', []byte("md5"+outer+"\x00"))
+	default:
+		return fmt.Errorf("unsupported authentication method %d", code)
+	}
+}
+
+// query sends a simple query and waits for ReadyForQuery.
+func (l *pgListener) query(sql string) error {
+	if err := l.writeMessage('Q', []byte(sql+"\x00")); err != nil {
+		return err
+	}
+	for {
+		typ, payload, err := l.readMessage()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'E':
+			return fmt.Errorf("postgres: %s", errorMessage(payload))
+		case 'Z':
+			return nil
+		}
+	}
+}
+
+// receive delivers NotificationResponse payloads until the connection
+// drops, then closes the channel. Slow consumers drop notifications
+// rather than backing up the socket.
+func (l *pgListener) receive() {
+	defer close(l.notifications)
+	for {
+		typ, payload, err := l.readMessage()
+		if err != nil {
+			return
+		}
+		if typ != 'A' || len(payload) < 4 {
+			continue
+		}
+		// NotificationResponse: int32 sender PID, channel, payload.
+		rest := payload[4:]
+		if i := indexNul(rest); i >= 0 {
+			rest = rest[i+1:] // skip the channel name
+		}
+		if i := indexNul(rest); i >= 0 {
+			select {
+			case l.notifications <- string(rest[:i]):
+			default:
+			}
+		}
+	}
+}
+
+// readMessage reads one backend message: type byte, int32 length
+// (including itself), payload.
+func (l *pgListener) readMessage() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(l.r, header); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:])) - 4
+	if length < 0 || length > 1<<20 {
+		return 0, nil, fmt.Errorf("implausible message length %d", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(l.r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+// writeMessage sends one frontend message with the standard framing.
+func (l *pgListener) writeMessage(typ byte, payload []byte) error {
+	buf := make([]byte, 5, 5+len(payload))
+	buf[0] = typ
+	binary.BigEndian.PutUint32(buf[1:], uint32(4+len(payload)))
+	_, err := l.conn.Write(append(buf, payload...))
+	return err
+}
+
+// errorMessage pulls the human-readable message ('M' field) out of an
+// ErrorResponse's key-prefixed C-string fields.
+func errorMessage(payload []byte) string {
+	for len(payload) > 0 && payload[0] != 0 {
+		key := payload[0]
+		rest := payload[1:]
+		i := indexNul(rest)
+		if i < 0 {
+			break
+		}
+		if key == 'M' {
+			return string(rest[:i])
+		}
+		payload = rest[i+1:]
+	}
+	return "unknown error"
+}
+
+func indexNul(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func md5Hex(b []byte) string {
+	sum := md5.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
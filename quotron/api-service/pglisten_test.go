@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBackend speaks just enough of the Postgres backend protocol to
+// authenticate a listener and push one notification.
+type fakeBackend struct {
+	conn net.Conn
+}
+
+func (b *fakeBackend) send(typ byte, payload []byte) {
+	buf := make([]byte, 5, 5+len(payload))
+	buf[0] = typ
+	binary.BigEndian.PutUint32(buf[1:], uint32(4+len(payload)))
+	b.conn.Write(append(buf, payload...))
+}
+
+func (b *fakeBackend) sendAuthOK()      { b.send('R', []byte{0, 0, 0, 0}) }
+func (b *fakeBackend) sendReady()       { b.send('Z', []byte{'I'}) }
+func (b *fakeBackend) readStartup() int { return b.readFramed() }
+func (b *fakeBackend) readMessage() byte {
+	typ := make([]byte, 1)
+	io.ReadFull(b.conn, typ)
+	b.readFramed()
+	return typ[0]
+}
+
+// readFramed consumes one length-prefixed payload. Startup messages have
+// no type byte, so their length was not yet consumed either way.
+func (b *fakeBackend) readFramed() int {
+	lenBuf := make([]byte, 4)
+	if _, err := io.ReadFull(b.conn, lenBuf); err != nil {
+		return 0
+	}
+	n := int(binary.BigEndian.Uint32(lenBuf)) - 4
+	if n > 0 {
+		io.ReadFull(b.conn, make([]byte, n))
+	}
+	return n
+}
+
+func (b *fakeBackend) sendNotification(channel, payload string) {
+	msg := []byte{0, 0, 0, 1} // sender PID
+	msg = append(msg, channel...)
+	msg = append(msg, 0)
+	msg = append(msg, payload...)
+	msg = append(msg, 0)
+	b.send('A', msg)
+}
+
+func TestListenerReceivesNotifications(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b := &fakeBackend{conn: conn}
+		b.readStartup()
+		b.sendAuthOK()
+		b.sendReady()
+		b.readMessage() // LISTEN query
+		b.sendReady()
+		b.sendNotification("quotron_quotes", `{"symbol":"AAPL","price":123.45}`)
+	}()
+
+	listener, err := dialPGListener("postgres://user:pass@"+ln.Addr().String()+"/quotron?sslmode=disable", "quotron_quotes")
+	if err != nil {
+		t.Fatalf("dialPGListener: %v", err)
+	}
+	defer listener.Close()
+
+	select {
+	case payload := <-listener.Notifications():
+		if !strings.Contains(payload, `"symbol":"AAPL"`) {
+			t.Errorf("payload = %q", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification delivered")
+	}
+}
+
+func TestListenerAnswersMD5Challenge(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	password := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b := &fakeBackend{conn: conn}
+		b.readStartup()
+		b.send('R', []byte{0, 0, 0, 5, 1, 2, 3, 4}) // MD5 with salt
+
+		typ := make([]byte, 1)
+		io.ReadFull(conn, typ)
+		lenBuf := make([]byte, 4)
+		io.ReadFull(conn, lenBuf)
+		body := make([]byte, binary.BigEndian.Uint32(lenBuf)-4)
+		io.ReadFull(conn, body)
+		password <- string(body)
+
+		b.sendAuthOK()
+		b.sendReady()
+		b.readMessage() // LISTEN query
+		b.sendReady()
+	}()
+
+	listener, err := dialPGListener("postgres://user:secret@"+ln.Addr().String()+"/quotron", "quotron_quotes")
+	if err != nil {
+		t.Fatalf("dialPGListener: %v", err)
+	}
+	defer listener.Close()
+
+	got := strings.TrimSuffix(<-password, "\x00")
+	inner := md5Hex([]byte("secret" + "user"))
+	want := "md5" + md5Hex(append([]byte(inner), 1, 2, 3, 4))
+	if got != want {
+		t.Errorf("password response = %q, want %q", got, want)
+	}
+}
+
+func TestListenerReportsAuthFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b := &fakeBackend{conn: conn}
+		b.readStartup()
+		b.send('E', []byte("SFATAL\x00Mpassword authentication failed\x00\x00"))
+	}()
+
+	_, err = dialPGListener("postgres://user:wrong@"+ln.Addr().String()+"/quotron", "quotron_quotes")
+	if err == nil || !strings.Contains(err.Error(), "password authentication failed") {
+		t.Errorf("err = %v, want the backend's error message", err)
+	}
+}
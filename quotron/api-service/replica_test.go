@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// routeDriver is a stub database/sql driver that records which logical
+// connection (by DSN) served each query, so we can assert read routing
+// without a real Postgres.
+type routeDriver struct{}
+
+var routeLog = struct {
+	sync.Mutex
+	queries map[string]int
+	failing map[string]bool
+}{queries: map[string]int{}, failing: map[string]bool{}}
+
+func (routeDriver) Open(name string) (driver.Conn, error) {
+	return &routeConn{name: name}, nil
+}
+
+type routeConn struct{ name string }
+
+func (c *routeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *routeConn) Close() error              { return nil }
+func (c *routeConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (c *routeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	routeLog.Lock()
+	defer routeLog.Unlock()
+	if routeLog.failing[c.name] {
+		return nil, errors.New(c.name + " is down")
+	}
+	routeLog.queries[c.name]++
+	return emptyRows{}, nil
+}
+
+func (c *routeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	routeLog.Lock()
+	defer routeLog.Unlock()
+	if routeLog.failing[c.name] {
+		return nil, errors.New(c.name + " is down")
+	}
+	routeLog.queries[c.name]++
+	return driver.RowsAffected(1), nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("routetest", routeDriver{})
+}
+
+func resetRouteLog() {
+	routeLog.Lock()
+	defer routeLog.Unlock()
+	routeLog.queries = map[string]int{}
+	routeLog.failing = map[string]bool{}
+}
+
+func queryCount(name string) int {
+	routeLog.Lock()
+	defer routeLog.Unlock()
+	return routeLog.queries[name]
+}
+
+func newReplicaStorage(t *testing.T) *Storage {
+	t.Helper()
+	primary, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replica, err := sql.Open("routetest", "replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := NewStorage(primary, ConflictIgnore)
+	storage.SetReplica(replica)
+	return storage
+}
+
+func TestReadsUseReplica(t *testing.T) {
+	resetRouteLog()
+	storage := newReplicaStorage(t)
+
+	if _, err := storage.GetQuoteHistory(context.Background(), "AAPL", 10); err != nil {
+		t.Fatalf("GetQuoteHistory: %v", err)
+	}
+
+	if got := queryCount("replica"); got != 1 {
+		t.Errorf("replica queries = %d, want 1", got)
+	}
+	if got := queryCount("primary"); got != 0 {
+		t.Errorf("primary queries = %d, want 0", got)
+	}
+}
+
+func TestReadsFallBackToPrimaryWhenReplicaDown(t *testing.T) {
+	resetRouteLog()
+	routeLog.Lock()
+	routeLog.failing["replica"] = true
+	routeLog.Unlock()
+	storage := newReplicaStorage(t)
+
+	if _, err := storage.GetQuoteHistory(context.Background(), "AAPL", 10); err != nil {
+		t.Fatalf("GetQuoteHistory: %v", err)
+	}
+
+	if got := queryCount("primary"); got != 1 {
+		t.Errorf("primary queries = %d, want 1 (fallback)", got)
+	}
+}
+
+func TestReadsUsePrimaryWithoutReplica(t *testing.T) {
+	resetRouteLog()
+	primary, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := NewStorage(primary, ConflictIgnore)
+
+	if _, err := storage.GetQuoteHistory(context.Background(), "AAPL", 10); err != nil {
+		t.Fatalf("GetQuoteHistory: %v", err)
+	}
+	if got := queryCount("primary"); got != 1 {
+		t.Errorf("primary queries = %d, want 1", got)
+	}
+}
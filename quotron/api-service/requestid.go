@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+)
+
+// requestIDHeader is the header the ID is read from and echoed to.
+const requestIDHeader = "X-Request-ID"
+
+// ctxKey is unexported so only this package can store request values.
+type ctxKey int
+
+const requestIDKey ctxKey = iota
+
+// newRequestID generates a random 16-hex-digit ID for requests that
+// arrive without one.
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside a
+// request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// reqLog is log.Printf with the context's request ID prefixed, so log
+// lines from concurrent requests can be told apart.
+func reqLog(ctx context.Context, format string, args ...interface{}) {
+	if id := RequestID(ctx); id != "" {
+		log.Printf("[%s] "+format, append([]interface{}{id}, args...)...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// requestIDMiddleware reads the incoming X-Request-ID (or generates
+// one), stores it in the request context, and echoes it back in the
+// response so clients and logs can correlate.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
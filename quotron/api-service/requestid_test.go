@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDEchoesIncomingHeader(t *testing.T) {
+	var seen string
+	handler := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "req-123" {
+		t.Errorf("context ID = %q, want req-123", seen)
+	}
+	if got := rec.Header().Get(requestIDHeader); got != "req-123" {
+		t.Errorf("response header = %q, want req-123", got)
+	}
+}
+
+func TestRequestIDGeneratedWhenMissing(t *testing.T) {
+	handler := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get(requestIDHeader) == "" {
+		t.Error("middleware should generate an ID when none arrives")
+	}
+}
+
+func TestReqLogIncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	handler := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqLog(r.Context(), "WARNING: something failed")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestIDHeader, "trace-me")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "[trace-me] WARNING: something failed") {
+		t.Errorf("log output %q should carry the request ID", buf.String())
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/client"
+	"github.com/we-be/tiny-ria/quotron/models"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// Config carries the command-line configuration for the API service.
+type Config struct {
+	Port          int
+	DatabaseURL   string
+	ReplicaURL    string
+	ProxyURL      string
+	NoCompression bool
+	// Mock swaps the upstream client for MockDataClient and disables DB
+	// writes, so the service runs fully offline for development.
+	Mock bool
+	// ConflictPolicy controls how quote inserts resolve (symbol,
+	// timestamp) collisions.
+	ConflictPolicy ConflictPolicy
+	// QueryTimeout bounds every individual DB query; 0 means the
+	// built-in default, negative disables the bound.
+	QueryTimeout time.Duration
+	// SlowQuery is the threshold past which a query is logged as slow;
+	// 0 means the built-in default, negative disables the logging.
+	SlowQuery time.Duration
+	// MaxBatchBytes caps the response size of batch endpoints; 0 means
+	// the built-in default, negative disables the cap.
+	MaxBatchBytes int
+	// DefaultIndices is the index set served by /api/indices.
+	DefaultIndices []string
+	// DefaultSymbols is the equity set the dashboard polls.
+	DefaultSymbols []string
+	// DefaultCryptos is the coin set the dashboard aggregate serves.
+	DefaultCryptos []string
+	// DashboardRefresh is how often the dashboard polls; 0 means the
+	// built-in default.
+	DashboardRefresh time.Duration
+	// MaxQuoteAge rejects quotes older than this instead of serving
+	// them as if they were live; 0 disables the gate.
+	MaxQuoteAge time.Duration
+	// StaleFallback serves the last stored quote, marked stale, when
+	// the live fetch fails — degraded data beats an error page during a
+	// brief upstream outage.
+	StaleFallback bool
+	// StaleMaxAge is the oldest stored quote StaleFallback may serve;
+	// 0 means any age.
+	StaleMaxAge time.Duration
+	// HealthRetention bounds how far back health_history reaches; rows
+	// older than this are pruned periodically. 0 keeps everything.
+	HealthRetention time.Duration
+	// DisabledFeatures lists experimental features whose routes are
+	// not registered (see knownFeatures).
+	DisabledFeatures []string
+	// TraceEndpoint is where finished spans are posted; empty disables
+	// export (spans are still propagated).
+	TraceEndpoint string
+	// AutoStore is the default for writing fetched quotes and indices
+	// back to the DB; individual requests override it with ?store=.
+	AutoStore bool
+	// DataClient names the registered upstream data source (see
+	// client.RegisterClient); the proxy is the default. ProxyURL doubles
+	// as the source's config string.
+	DataClient string
+	// StoreBackend selects the storage implementation; see openStorage.
+	StoreBackend string
+	// StorePath is the database file for file-backed backends (sqlite).
+	StorePath string
+	// WebhookSecrets maps webhook source names to their HMAC secrets;
+	// only listed sources may push data.
+	WebhookSecrets map[string]string
+	// CryptoQuote is the quote currency bare coin symbols pair with;
+	// individual requests override it with ?quote=. Empty means USD.
+	CryptoQuote string
+}
+
+// knownFeatures are the experimental endpoints that can be toggled off
+// with --disable-features.
+var knownFeatures = []string{"correlation", "intraday", "stats"}
+
+// Server wires the HTTP handlers to storage and the upstream data client.
+type Server struct {
+	cfg     Config
+	storage *Storage
+	client  client.DataClient
+	broker  *quoteBroker
+	metrics *metricsRegistry
+	health  healthWatcher
+
+	// Write-back queue: one background writer drains storeCh so request
+	// handlers never block on (or spawn goroutines for) DB writes.
+	storeOnce sync.Once
+	storeCh   chan *models.StockQuote
+	flushed   chan struct{}
+}
+
+// NewServer connects to storage (if configured) and builds a Server.
+// A missing database is not fatal: the service degrades to proxy-only mode.
+func NewServer(cfg Config) (*Server, error) {
+	storage, err := openStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if storage != nil && cfg.ReplicaURL != "" {
+		replica, err := sql.Open("postgres", cfg.ReplicaURL)
+		if err != nil {
+			log.Printf("WARNING: opening read replica: %v", err)
+		} else if err := replica.Ping(); err != nil {
+			log.Printf("WARNING: read replica unreachable, reads stay on primary: %v", err)
+		} else {
+			storage.SetReplica(replica)
+			log.Printf("routing read queries to replica")
+		}
+	}
+
+	name := cfg.DataClient
+	if name == "" {
+		name = "proxy"
+	}
+	dataClient, err := client.NewDataClient(name, cfg.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Mock {
+		log.Printf("running in mock mode: serving synthetic data, DB writes disabled")
+		dataClient = NewMockDataClient()
+	}
+
+	return &Server{
+		cfg:     cfg,
+		storage: storage,
+		// The manager collapses concurrent fetches of one symbol (batch
+		// fan-out, stream bridge) into a single upstream call.
+		client: client.NewClientManager(dataClient),
+		broker: newQuoteBroker(),
+	}, nil
+}
+
+// openStorage builds the storage backend selected by --store. Only
+// Postgres is built in today; the sqlite option is reserved for a pure-Go
+// driver (no CGO) so contributors can run without external services, and
+// errors out until that driver is vendored. An unreachable Postgres is
+// not fatal — the service degrades to proxy-only mode — but asking for a
+// backend that does not exist is.
+func openStorage(cfg Config) (*Storage, error) {
+	switch cfg.StoreBackend {
+	case "", "postgres":
+	case "sqlite":
+		return nil, fmt.Errorf("store backend sqlite is not built in yet (needs a pure-Go sqlite driver); use postgres")
+	default:
+		return nil, fmt.Errorf("unknown store backend %q (want postgres)", cfg.StoreBackend)
+	}
+
+	if cfg.DatabaseURL == "" {
+		return nil, nil
+	}
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		log.Printf("WARNING: database unreachable, running without storage: %v", err)
+		return nil, nil
+	}
+	storage := NewStorage(db, cfg.ConflictPolicy)
+	if cfg.QueryTimeout != 0 {
+		storage.SetQueryTimeout(cfg.QueryTimeout)
+	}
+	if cfg.SlowQuery != 0 {
+		storage.SetSlowQueryThreshold(cfg.SlowQuery)
+	}
+	reportSchemaDrift(db)
+	return storage, nil
+}
+
+// reportSchemaDrift runs the startup self-check comparing model fields
+// to the live table columns, so a missed migration shows up at boot
+// instead of as insert failures at runtime.
+func reportSchemaDrift(db *sql.DB) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	drifts, err := models.CheckSchemaDrift(ctx, db)
+	if err != nil {
+		log.Printf("WARNING: schema drift check failed: %v", err)
+		return
+	}
+	for _, drift := range drifts {
+		log.Printf("ERROR: schema drift: %s (run migrations or regenerate the models)", drift)
+	}
+}
+
+// featureEnabled reports whether an experimental feature's routes
+// should be registered. Everything is on unless explicitly disabled.
+func (s *Server) featureEnabled(name string) bool {
+	for _, disabled := range s.cfg.DisabledFeatures {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+// setupRoutes builds the route table. Experimental endpoints are gated
+// behind feature flags so operators can toggle them off. Every route is
+// instrumented under its pattern for the /metrics endpoint.
+func (s *Server) setupRoutes() *http.ServeMux {
+	if s.metrics == nil {
+		s.metrics = newMetricsRegistry()
+	}
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.HandleFunc(pattern, s.metrics.instrument(pattern, h))
+	}
+	handle("/api/quote/", s.getQuoteHandler)
+	handle("/api/index/", s.getIndexHandler)
+	handle("/api/indices", s.indicesHandler)
+	handle("/api/symbols", s.symbolsHandler)
+	handle("/api/quotes/batch", s.batchQuotesHandler)
+	handle("/api/quotes/compare", s.compareQuotesHandler)
+	handle("/api/dashboard", s.dashboardDataHandler)
+	handle("/api/crypto/batch", s.cryptoBatchHandler)
+	handle("/api/cryptos", s.cryptosHandler)
+	handle("/api/stream", s.streamHandler)
+	handle("/api/ingest/webhook/", s.webhookIngestHandler)
+	handle("/api/batches/", s.batchStatusHandler)
+	if s.featureEnabled("correlation") {
+		handle("/api/correlation", s.correlationHandler)
+	}
+	handle("/api/version", s.versionHandler)
+	handle("/health", s.healthHandler)
+	handle("/healthz", s.healthzHandler)
+	handle("/health/db", s.dbStatsHandler)
+	handle("/health/history", s.healthHistoryHandler)
+	handle("/readyz", s.readyzHandler)
+	mux.HandleFunc("/metrics", s.metricsHandler)
+	handle("/", s.dashboardHandler)
+	return mux
+}
+
+// Start registers the routes and blocks serving HTTP.
+func (s *Server) Start() error {
+	mux := s.setupRoutes()
+	s.startQuoteBridge()
+	if s.storage != nil && s.cfg.HealthRetention > 0 {
+		go s.healthPruneLoop(context.Background())
+	}
+
+	if s.cfg.TraceEndpoint != "" {
+		trace.SetExporter(trace.NewHTTPExporter(s.cfg.TraceEndpoint))
+		log.Printf("exporting trace spans to %s", s.cfg.TraceEndpoint)
+	}
+
+	// Middleware chain, innermost first: CORS, then compression so the
+	// compressed body is what crosses the wire, then tracing and request
+	// IDs outermost so the request span carries the ID and every log
+	// line below can too.
+	var handler http.Handler = corsMiddleware(mux)
+	if !s.cfg.NoCompression {
+		handler = gzipMiddleware(handler)
+	}
+	handler = tracingMiddleware(handler)
+	// Recovery sits just inside the request ID so a panic log carries
+	// the ID, while still catching panics from every layer below.
+	handler = recoverMiddleware(handler)
+	handler = requestIDMiddleware(handler)
+
+	addr := fmt.Sprintf(":%d", s.cfg.Port)
+	log.Printf("API service listening on %s", addr)
+	return http.ListenAndServe(addr, handler)
+}
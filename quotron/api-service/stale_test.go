@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// staleDriver is a stub database/sql driver whose quote table holds
+// exactly one AAPL row; tests move its timestamp to simulate stored
+// data of varying staleness.
+type staleDriver struct{}
+
+var staleRow = struct {
+	sync.Mutex
+	ts time.Time
+}{}
+
+func setStaleRowTime(ts time.Time) {
+	staleRow.Lock()
+	defer staleRow.Unlock()
+	staleRow.ts = ts
+}
+
+func (staleDriver) Open(name string) (driver.Conn, error) { return staleConn{}, nil }
+
+type staleConn struct{}
+
+func (staleConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (staleConn) Close() error              { return nil }
+func (staleConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (staleConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	staleRow.Lock()
+	defer staleRow.Unlock()
+	return &staleRows{ts: staleRow.ts}, nil
+}
+
+type staleRows struct {
+	ts   time.Time
+	done bool
+}
+
+func (r *staleRows) Columns() []string {
+	return []string{"symbol", "price", "change", "change_percent",
+		"volume", "high_24h", "low_24h", "timestamp", "exchange", "source"}
+}
+func (r *staleRows) Close() error { return nil }
+func (r *staleRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	row := []driver.Value{"AAPL", 187.32, -1.2, -0.64,
+		int64(1000000), 190.0, 185.0, r.ts, "NASDAQ", "api-scraper"}
+	copy(dest, row)
+	return nil
+}
+
+func init() {
+	sql.Register("staletest", staleDriver{})
+}
+
+// staleServer builds a Server whose storage serves the canned AAPL row
+// and whose upstream is down.
+func staleServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+	db, err := sql.Open("staletest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{
+		cfg:     cfg,
+		storage: NewStorage(db, ConflictIgnore),
+		client:  erroringClient{err: errors.New("proxy is down")},
+	}
+}
+
+func getQuote(s *Server) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil)
+	rec := httptest.NewRecorder()
+	s.getQuoteHandler(rec, req)
+	return rec
+}
+
+func TestStaleFallbackServesMarkedQuote(t *testing.T) {
+	setStaleRowTime(time.Now().Add(-10 * time.Minute))
+	s := staleServer(t, Config{
+		Mock:          true,
+		MaxQuoteAge:   time.Minute,
+		StaleFallback: true,
+		StaleMaxAge:   time.Hour,
+	})
+
+	rec := getQuote(s)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Symbol string  `json:"symbol"`
+		Price  float64 `json:"price"`
+		Stale  bool    `json:"stale"`
+		Age    string  `json:"age"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Symbol != "AAPL" || resp.Price != 187.32 {
+		t.Errorf("quote = %s @ %v, want the stored AAPL row", resp.Symbol, resp.Price)
+	}
+	if !resp.Stale {
+		t.Error("fallback response should be flagged stale")
+	}
+	if resp.Age == "" {
+		t.Error("fallback response should carry the quote's age")
+	}
+}
+
+func TestStaleFallbackRespectsMaxAge(t *testing.T) {
+	setStaleRowTime(time.Now().Add(-2 * time.Hour))
+	s := staleServer(t, Config{
+		Mock:          true,
+		MaxQuoteAge:   time.Minute,
+		StaleFallback: true,
+		StaleMaxAge:   time.Hour,
+	})
+
+	if rec := getQuote(s); rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502: a 2h-old row is past the 1h stale bound", rec.Code)
+	}
+}
+
+func TestStaleFallbackIsOffByDefault(t *testing.T) {
+	setStaleRowTime(time.Now().Add(-10 * time.Minute))
+	s := staleServer(t, Config{Mock: true, MaxQuoteAge: time.Minute})
+
+	if rec := getQuote(s); rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want the upstream error without the flag", rec.Code)
+	}
+}
+
+func TestFreshStoredQuoteIsNotMarkedStale(t *testing.T) {
+	setStaleRowTime(time.Now())
+	s := staleServer(t, Config{
+		Mock:          true,
+		MaxQuoteAge:   time.Minute,
+		StaleFallback: true,
+		StaleMaxAge:   time.Hour,
+	})
+
+	rec := getQuote(s)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp["stale"]; ok {
+		t.Error("a fresh stored quote should be served as live, not marked stale")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuoteStats is the payload for /api/quote/{symbol}/stats: derived
+// statistics over the stored price series in the requested window.
+type QuoteStats struct {
+	Symbol        string  `json:"symbol"`
+	Window        string  `json:"window"`
+	DataPoints    int     `json:"data_points"`
+	MovingAverage float64 `json:"moving_average"`
+	StdDev        float64 `json:"std_dev"`
+	Min           float64 `json:"min"`
+	Max           float64 `json:"max"`
+	// Volatility is the standard deviation of tick-over-tick returns.
+	Volatility float64   `json:"volatility"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
+
+// parseStatsWindow accepts windows like 20d, 48h, or 2w.
+func parseStatsWindow(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	switch s[len(s)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid window %q (want e.g. 48h, 20d, 2w)", s)
+}
+
+// statsHandler serves /api/quote/{symbol}/stats?window=20d from the
+// stored quote series.
+func (s *Server) statsHandler(w http.ResponseWriter, r *http.Request, symbol string) {
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "storage not available")
+		return
+	}
+
+	windowArg := r.URL.Query().Get("window")
+	if windowArg == "" {
+		windowArg = "20d"
+	}
+	window, err := parseStatsWindow(windowArg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	since := time.Now().UTC().Add(-window)
+	quotes, err := s.storage.GetQuotesSince(r.Context(), symbol, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load series: "+err.Error())
+		return
+	}
+	if len(quotes) < 2 {
+		writeError(w, http.StatusNotFound,
+			fmt.Sprintf("only %d data points stored for %s in %s; need at least 2",
+				len(quotes), symbol, windowArg))
+		return
+	}
+
+	prices := make([]float64, len(quotes))
+	for i, q := range quotes {
+		prices[i] = q.Price
+	}
+
+	stats := QuoteStats{
+		Symbol:     strings.ToUpper(symbol),
+		Window:     windowArg,
+		DataPoints: len(prices),
+		From:       quotes[0].Timestamp,
+		To:         quotes[len(quotes)-1].Timestamp,
+	}
+	stats.MovingAverage, stats.StdDev = meanStdDev(prices)
+	stats.Min, stats.Max = minMax(prices)
+	stats.Volatility = returnsVolatility(prices)
+
+	writeJSONWithETag(w, r, &stats)
+}
+
+// meanStdDev returns the mean and sample standard deviation of xs.
+func meanStdDev(xs []float64) (mean, stddev float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	var sumSq float64
+	for _, x := range xs {
+		d := x - mean
+		sumSq += d * d
+	}
+	return mean, math.Sqrt(sumSq / float64(len(xs)-1))
+}
+
+func minMax(xs []float64) (min, max float64) {
+	min, max = xs[0], xs[0]
+	for _, x := range xs[1:] {
+		if x < min {
+			min = x
+		}
+		if x > max {
+			max = x
+		}
+	}
+	return min, max
+}
+
+// returnsVolatility is the sample standard deviation of simple
+// tick-over-tick returns.
+func returnsVolatility(prices []float64) float64 {
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] == 0 {
+			continue
+		}
+		returns = append(returns, prices[i]/prices[i-1]-1)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+	_, stddev := meanStdDev(returns)
+	return stddev
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/client"
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// erroringClient fails every fetch with a fixed error.
+type erroringClient struct {
+	err error
+}
+
+func (c erroringClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	return nil, c.err
+}
+
+func (c erroringClient) GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error) {
+	return nil, c.err
+}
+
+func (c erroringClient) GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error) {
+	return nil, c.err
+}
+
+func TestUpstreamErrorsMapToAccurateStatuses(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"wrapped deadline", fmt.Errorf("proxy: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"not found", fmt.Errorf("/quote/NOPE: %w", client.ErrNotFound), http.StatusNotFound},
+		{"other failure", fmt.Errorf("upstream returned 500"), http.StatusBadGateway},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := &Server{cfg: Config{Mock: true}, client: erroringClient{err: c.err}}
+			req := httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil)
+			rec := httptest.NewRecorder()
+			s.getQuoteHandler(rec, req)
+
+			if rec.Code != c.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, c.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestIndexHandlerMapsUpstreamTimeout(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: erroringClient{err: context.DeadlineExceeded}}
+	req := httptest.NewRequest(http.MethodGet, "/api/index/SPX", nil)
+	rec := httptest.NewRecorder()
+	s.getIndexHandler(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504 (body %s)", rec.Code, rec.Body.String())
+	}
+}
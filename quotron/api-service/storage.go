@@ -0,0 +1,528 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/db"
+	"github.com/we-be/tiny-ria/quotron/ingest"
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// ConflictPolicy controls what InsertQuote does when a row collides
+// with the uniqueness constraint on (symbol, timestamp).
+type ConflictPolicy string
+
+const (
+	// ConflictError surfaces the constraint violation to the caller.
+	ConflictError ConflictPolicy = "error"
+	// ConflictIgnore keeps the existing row (ON CONFLICT DO NOTHING).
+	ConflictIgnore ConflictPolicy = "ignore"
+	// ConflictUpdate overwrites the existing row with the new values.
+	ConflictUpdate ConflictPolicy = "update"
+)
+
+// ParseConflictPolicy validates a policy string from configuration.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch ConflictPolicy(s) {
+	case ConflictError, ConflictIgnore, ConflictUpdate:
+		return ConflictPolicy(s), nil
+	}
+	return "", fmt.Errorf("invalid conflict policy %q (want error, ignore, or update)", s)
+}
+
+// defaultQueryTimeout bounds every individual query so a hung
+// connection cannot stall a request indefinitely.
+const defaultQueryTimeout = 5 * time.Second
+
+// defaultSlowQueryThreshold is how long a query may take before it is
+// logged as slow.
+const defaultSlowQueryThreshold = 500 * time.Millisecond
+
+// Storage wraps the Postgres connections used by the API service:
+// writes always go to the primary, reads prefer the replica when one is
+// configured.
+type Storage struct {
+	db           *sql.DB // primary
+	replica      *sql.DB // optional read replica
+	policy       ConflictPolicy
+	queryTimeout time.Duration
+	slowQuery    time.Duration
+}
+
+// NewStorage builds a Storage on top of an open database handle.
+func NewStorage(db *sql.DB, policy ConflictPolicy) *Storage {
+	return &Storage{
+		db:           db,
+		policy:       policy,
+		queryTimeout: defaultQueryTimeout,
+		slowQuery:    defaultSlowQueryThreshold,
+	}
+}
+
+// Ping reports whether the primary database answers.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// HasTrigger reports whether a trigger with the given name exists, so
+// optional trigger-backed features can disable themselves cleanly on
+// databases that have not applied the migration.
+func (s *Storage) HasTrigger(name string) (bool, error) {
+	ctx, cancel := s.opCtx(context.Background())
+	defer cancel()
+
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pg_trigger WHERE tgname = $1", name).Scan(&count)
+	return count > 0, err
+}
+
+// SetReplica routes read queries to the given replica connection.
+func (s *Storage) SetReplica(replica *sql.DB) {
+	s.replica = replica
+}
+
+// SetQueryTimeout overrides the per-query timeout; d <= 0 disables it.
+func (s *Storage) SetQueryTimeout(d time.Duration) {
+	s.queryTimeout = d
+}
+
+// SetSlowQueryThreshold overrides the duration past which a query is
+// logged as slow; d <= 0 disables the logging.
+func (s *Storage) SetSlowQueryThreshold(d time.Duration) {
+	s.slowQuery = d
+}
+
+// logSlow reports a query that exceeded the slow-query threshold.
+// Intended as `defer s.logSlow("GetQuoteHistory", query, time.Now())`.
+// The query text is safe to log: values travel as $n parameters, so the
+// statement never contains user data.
+func (s *Storage) logSlow(name, query string, start time.Time) {
+	if s.slowQuery <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed >= s.slowQuery {
+		log.Printf("WARNING: slow query %s took %v (threshold %v): %s",
+			name, elapsed, s.slowQuery, condenseSQL(query))
+	}
+}
+
+// condenseSQL collapses a statement onto one line and truncates it so a
+// slow-query log entry stays readable.
+func condenseSQL(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > 120 {
+		query = query[:120] + "..."
+	}
+	return query
+}
+
+// Stats reports the connection-pool counters per database, for the
+// health endpoint. Keys are "primary" and, when configured, "replica".
+func (s *Storage) Stats() map[string]sql.DBStats {
+	stats := map[string]sql.DBStats{"primary": s.db.Stats()}
+	if s.replica != nil {
+		stats["replica"] = s.replica.Stats()
+	}
+	return stats
+}
+
+// opCtx derives the bounded context every query runs under. The caller
+// must hold the cancel func until it has finished consuming rows.
+func (s *Storage) opCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.queryTimeout)
+}
+
+// readQuery runs a read on the replica when configured, falling back to
+// the primary if the replica errors (down, lagging restart, ...).
+func (s *Storage) readQuery(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if s.replica != nil {
+		rows, err := s.replica.QueryContext(ctx, query, args...)
+		if err == nil {
+			return rows, nil
+		}
+		log.Printf("WARNING: replica query failed, falling back to primary: %v", err)
+	}
+	return s.db.QueryContext(ctx, query, args...)
+}
+
+// GetLatestQuote returns the most recent stored quote for a symbol.
+func (s *Storage) GetLatestQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source
+		FROM stock_quotes
+		WHERE symbol = $1
+		ORDER BY timestamp DESC
+		LIMIT 1`
+	defer s.logSlow("GetLatestQuote", query, time.Now())
+	rows, err := s.readQuery(ctx, query, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+	return scanQuote(rows)
+}
+
+// SymbolInfo summarizes one symbol's coverage in stock_quotes: how many
+// quotes we hold and when the newest one arrived.
+type SymbolInfo struct {
+	Symbol   string    `json:"symbol"`
+	Quotes   int64     `json:"quotes"`
+	LatestAt time.Time `json:"latest_at"`
+}
+
+// ListSymbols returns the distinct symbols present in stock_quotes,
+// alphabetically, each with its quote count and latest timestamp. A
+// non-empty exchange restricts the universe to that venue; limit and
+// offset page the result.
+func (s *Storage) ListSymbols(ctx context.Context, exchange string, limit, offset int) ([]SymbolInfo, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT symbol, COUNT(*), MAX(timestamp)
+		FROM stock_quotes
+		WHERE $1 = '' OR exchange = $1
+		GROUP BY symbol
+		ORDER BY symbol
+		LIMIT $2 OFFSET $3`
+	defer s.logSlow("ListSymbols", query, time.Now())
+	rows, err := s.readQuery(ctx, query, exchange, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []SymbolInfo
+	for rows.Next() {
+		var info SymbolInfo
+		if err := rows.Scan(&info.Symbol, &info.Quotes, &info.LatestAt); err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, info)
+	}
+	return symbols, rows.Err()
+}
+
+// HealthChange is one row of a source's status timeline: what the
+// status became and when it changed.
+type HealthChange struct {
+	Source string    `json:"source"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// RecordHealthChange appends one status transition to health_history.
+// Callers detect the transition; this just persists it.
+func (s *Storage) RecordHealthChange(ctx context.Context, source, status string, at time.Time) error {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		INSERT INTO health_history (source, status, changed_at)
+		VALUES ($1, $2, $3)`
+	defer s.logSlow("RecordHealthChange", query, time.Now())
+	_, err := s.db.ExecContext(ctx, query, source, status, at)
+	return err
+}
+
+// HealthHistory returns the status changes from since onwards, oldest
+// first. An empty source returns every source's timeline interleaved.
+func (s *Storage) HealthHistory(ctx context.Context, source string, since time.Time) ([]HealthChange, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT source, status, changed_at
+		FROM health_history
+		WHERE ($1 = '' OR source = $1) AND changed_at >= $2
+		ORDER BY changed_at ASC`
+	defer s.logSlow("HealthHistory", query, time.Now())
+	rows, err := s.readQuery(ctx, query, source, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []HealthChange
+	for rows.Next() {
+		var c HealthChange
+		if err := rows.Scan(&c.Source, &c.Status, &c.At); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// LastHealthChangeBefore returns the newest status change for a source
+// strictly before the given time — the state a window opened in — or
+// sql.ErrNoRows when the timeline does not reach back that far.
+func (s *Storage) LastHealthChangeBefore(ctx context.Context, source string, at time.Time) (*HealthChange, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT source, status, changed_at
+		FROM health_history
+		WHERE source = $1 AND changed_at < $2
+		ORDER BY changed_at DESC
+		LIMIT 1`
+	defer s.logSlow("LastHealthChangeBefore", query, time.Now())
+	rows, err := s.readQuery(ctx, query, source, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+	var c HealthChange
+	if err := rows.Scan(&c.Source, &c.Status, &c.At); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// PruneHealthHistory deletes status changes older than the cutoff and
+// reports how many rows went.
+func (s *Storage) PruneHealthHistory(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `DELETE FROM health_history WHERE changed_at < $1`
+	defer s.logSlow("PruneHealthHistory", query, time.Now())
+	res, err := s.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetQuoteHistory returns up to limit stored quotes for a symbol, newest first.
+func (s *Storage) GetQuoteHistory(ctx context.Context, symbol string, limit int) ([]*models.StockQuote, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source
+		FROM stock_quotes
+		WHERE symbol = $1
+		ORDER BY timestamp DESC
+		LIMIT $2`
+	defer s.logSlow("GetQuoteHistory", query, time.Now())
+	rows, err := s.readQuery(ctx, query, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*models.StockQuote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, rows.Err()
+}
+
+// QueryQuotes returns stored quotes matching the filter, newest first.
+// The SQL is assembled by the shared builder, so every filter value is
+// passed as a query argument rather than spliced into the statement.
+func (s *Storage) QueryQuotes(ctx context.Context, filter db.QuoteFilter) ([]*models.StockQuote, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query, args := db.BuildQuoteQuery(filter)
+	defer s.logSlow("QueryQuotes", query, time.Now())
+	rows, err := s.readQuery(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*models.StockQuote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, rows.Err()
+}
+
+// GetQuotesSince returns all stored quotes for a symbol from since
+// onwards, oldest first (the order the stats computations expect).
+func (s *Storage) GetQuotesSince(ctx context.Context, symbol string, since time.Time) ([]*models.StockQuote, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		SELECT symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source
+		FROM stock_quotes
+		WHERE symbol = $1 AND timestamp >= $2
+		ORDER BY timestamp ASC`
+	defer s.logSlow("GetQuotesSince", query, time.Now())
+	rows, err := s.readQuery(ctx, query, symbol, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*models.StockQuote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, rows.Err()
+}
+
+// GetIntradayBuckets aggregates stored quotes since the given time into
+// OHLC buckets of the given width, date_trunc-style: timestamps are
+// floored to the bucket boundary in SQL so Postgres does the grouping.
+func (s *Storage) GetIntradayBuckets(ctx context.Context, symbol string, interval time.Duration, since time.Time) ([]models.OHLCBucket, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	seconds := int64(interval / time.Second)
+	query := `
+		SELECT
+			to_timestamp(floor(extract(epoch FROM timestamp) / $3) * $3) AS bucket,
+			(array_agg(price ORDER BY timestamp ASC))[1]  AS open,
+			MAX(price)                                    AS high,
+			MIN(price)                                    AS low,
+			(array_agg(price ORDER BY timestamp DESC))[1] AS close,
+			COALESCE(SUM(volume), 0)                      AS volume
+		FROM stock_quotes
+		WHERE symbol = $1 AND timestamp >= $2
+		GROUP BY bucket
+		ORDER BY bucket ASC`
+	defer s.logSlow("GetIntradayBuckets", query, time.Now())
+	rows, err := s.readQuery(ctx, query, symbol, since, seconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.OHLCBucket
+	for rows.Next() {
+		var b models.OHLCBucket
+		if err := rows.Scan(&b.Start, &b.Open, &b.High, &b.Low, &b.Close, &b.Volume); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// insertQuoteSQL builds the insert statement for the given policy.
+func insertQuoteSQL(policy ConflictPolicy) string {
+	base := `
+		INSERT INTO stock_quotes (symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	switch policy {
+	case ConflictIgnore:
+		return base + `
+		ON CONFLICT (symbol, timestamp) DO NOTHING`
+	case ConflictUpdate:
+		return base + `
+		ON CONFLICT (symbol, timestamp) DO UPDATE SET
+			price = EXCLUDED.price,
+			change = EXCLUDED.change,
+			change_percent = EXCLUDED.change_percent,
+			volume = EXCLUDED.volume,
+			high_24h = EXCLUDED.high_24h,
+			low_24h = EXCLUDED.low_24h,
+			exchange = EXCLUDED.exchange,
+			source = EXCLUDED.source`
+	}
+	return base
+}
+
+// InsertQuote writes one quote row, resolving (symbol, timestamp)
+// collisions according to the configured conflict policy.
+func (s *Storage) InsertQuote(ctx context.Context, q *models.StockQuote) error {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := insertQuoteSQL(s.policy)
+	defer s.logSlow("InsertQuote", query, time.Now())
+	_, err := s.db.ExecContext(ctx, query,
+		q.Symbol, q.Price, q.Change, q.ChangePercent, q.Volume, q.High24h, q.Low24h,
+		q.Timestamp, q.Exchange, q.Source)
+	return err
+}
+
+// canonicalIndex normalizes an index's symbol and name through the
+// shared alias table before storage, so the same index never fragments
+// its history across the names different sources use for it.
+func canonicalIndex(idx *models.MarketIndex) {
+	symbol, display := models.ResolveIndex(idx.Symbol)
+	idx.Symbol = symbol
+	idx.Name = display
+}
+
+// InsertIndex writes one market index row under its canonical name.
+func (s *Storage) InsertIndex(ctx context.Context, idx *models.MarketIndex) error {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	query := `
+		INSERT INTO market_indices (name, symbol, value, change, timestamp, source)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	defer s.logSlow("InsertIndex", query, time.Now())
+	canonicalIndex(idx)
+	_, err := s.db.ExecContext(ctx, query,
+		idx.Name, idx.Symbol, idx.Value, idx.Change, idx.Timestamp, idx.Source)
+	return err
+}
+
+// GetDataBatch loads one batch bookkeeping row for the status endpoint.
+// The SQL lives with the rest of the batch lifecycle in the ingest
+// package; this just runs it under the storage timeouts.
+func (s *Storage) GetDataBatch(ctx context.Context, id string) (*ingest.BatchRecord, error) {
+	ctx, cancel := s.opCtx(ctx)
+	defer cancel()
+	defer s.logSlow("GetDataBatch", "SELECT ... FROM data_batches WHERE id = $1", time.Now())
+	return ingest.GetBatchRecord(ctx, s.db, id)
+}
+
+// scanner matches both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanQuote(row scanner) (*models.StockQuote, error) {
+	var q models.StockQuote
+	var volume sql.NullInt64
+	var high, low sql.NullFloat64
+	err := row.Scan(&q.Symbol, &q.Price, &q.Change, &q.ChangePercent,
+		&volume, &high, &low, &q.Timestamp, &q.Exchange, &q.Source)
+	if err != nil {
+		return nil, err
+	}
+	if volume.Valid {
+		q.Volume = &volume.Int64
+	}
+	if high.Valid {
+		q.High24h = &high.Float64
+	}
+	if low.Valid {
+		q.Low24h = &low.Float64
+	}
+	return &q, nil
+}
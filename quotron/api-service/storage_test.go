@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func TestCanonicalIndexUnifiesSourceNames(t *testing.T) {
+	// The same index as three different sources report it.
+	variants := []*models.MarketIndex{
+		{Symbol: "^DJI", Name: "^DJI"},
+		{Symbol: "DOW", Name: "DOW"},
+		{Symbol: "Dow Jones", Name: "Dow Jones Industrial Average"},
+	}
+	for _, idx := range variants {
+		canonicalIndex(idx)
+		if idx.Symbol != "^DJI" || idx.Name != "Dow Jones" {
+			t.Errorf("canonicalIndex(%q) = (%q, %q), want (^DJI, Dow Jones)",
+				idx.Source, idx.Symbol, idx.Name)
+		}
+	}
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	for _, valid := range []string{"error", "ignore", "update"} {
+		if _, err := ParseConflictPolicy(valid); err != nil {
+			t.Errorf("ParseConflictPolicy(%q): %v", valid, err)
+		}
+	}
+	if _, err := ParseConflictPolicy("upsert"); err == nil {
+		t.Error("ParseConflictPolicy(upsert) should fail")
+	}
+}
+
+func TestInsertQuoteSQLPerPolicy(t *testing.T) {
+	errorSQL := insertQuoteSQL(ConflictError)
+	if strings.Contains(errorSQL, "ON CONFLICT") {
+		t.Errorf("error policy should not add ON CONFLICT:\n%s", errorSQL)
+	}
+
+	ignoreSQL := insertQuoteSQL(ConflictIgnore)
+	if !strings.Contains(ignoreSQL, "ON CONFLICT (symbol, timestamp) DO NOTHING") {
+		t.Errorf("ignore policy missing DO NOTHING:\n%s", ignoreSQL)
+	}
+
+	updateSQL := insertQuoteSQL(ConflictUpdate)
+	if !strings.Contains(updateSQL, "DO UPDATE SET") {
+		t.Errorf("update policy missing DO UPDATE:\n%s", updateSQL)
+	}
+	// Every non-key model column must be overwritten on update.
+	for _, col := range []string{"price", "change", "change_percent", "volume", "exchange", "source"} {
+		if !strings.Contains(updateSQL, col+" = EXCLUDED."+col) {
+			t.Errorf("update policy does not set %s:\n%s", col, updateSQL)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowDriver is a stub driver whose queries never return on their own:
+// they block until the query context is cancelled, simulating a hung
+// connection.
+type slowDriver struct{}
+
+func (slowDriver) Open(name string) (driver.Conn, error) { return slowConn{}, nil }
+
+type slowConn struct{}
+
+func (slowConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (slowConn) Close() error              { return nil }
+func (slowConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (slowConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func init() {
+	sql.Register("slowtest", slowDriver{})
+}
+
+func TestSlowQueryIsCancelledAtTimeout(t *testing.T) {
+	db, err := sql.Open("slowtest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := NewStorage(db, ConflictIgnore)
+	storage.SetQueryTimeout(20 * time.Millisecond)
+
+	start := time.Now()
+	_, err = storage.GetQuoteHistory(context.Background(), "AAPL", 10)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("query took %v to cancel, want ~20ms", elapsed)
+	}
+}
+
+func TestSlowQueryIsLogged(t *testing.T) {
+	db, err := sql.Open("slowtest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := NewStorage(db, ConflictIgnore)
+	storage.SetQueryTimeout(30 * time.Millisecond)
+	storage.SetSlowQueryThreshold(10 * time.Millisecond)
+
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	storage.GetQuoteHistory(context.Background(), "AAPL", 10)
+
+	out := buf.String()
+	if !strings.Contains(out, "slow query GetQuoteHistory") {
+		t.Errorf("log output %q should name the slow query", out)
+	}
+	if !strings.Contains(out, "FROM stock_quotes") {
+		t.Errorf("log output %q should include the query text", out)
+	}
+	if strings.Contains(out, "AAPL") {
+		t.Errorf("log output %q must carry placeholders, never parameter values", out)
+	}
+}
+
+func TestCondenseSQL(t *testing.T) {
+	got := condenseSQL("\n\t\tSELECT symbol\n\t\tFROM stock_quotes\n\t\tWHERE symbol = $1")
+	if want := "SELECT symbol FROM stock_quotes WHERE symbol = $1"; got != want {
+		t.Errorf("condenseSQL = %q, want %q", got, want)
+	}
+
+	long := condenseSQL(strings.Repeat("SELECT ", 40))
+	if len(long) != 123 || !strings.HasSuffix(long, "...") {
+		t.Errorf("long statements should be truncated, got %d bytes: %q", len(long), long)
+	}
+}
+
+func TestFastQueryIsNotLogged(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := NewStorage(db, ConflictIgnore)
+
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	if _, err := storage.GetQuoteHistory(context.Background(), "AAPL", 10); err != nil {
+		t.Fatalf("GetQuoteHistory: %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, "slow query") {
+		t.Errorf("fast query should not be logged as slow: %q", out)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenStorageRejectsUnbuiltSQLite(t *testing.T) {
+	_, err := openStorage(Config{StoreBackend: "sqlite", StorePath: "./quotron.db"})
+	if err == nil || !strings.Contains(err.Error(), "sqlite") {
+		t.Errorf("err = %v, want an explanation that sqlite is not built in", err)
+	}
+}
+
+func TestOpenStorageRejectsUnknownBackend(t *testing.T) {
+	_, err := openStorage(Config{StoreBackend: "mysql"})
+	if err == nil || !strings.Contains(err.Error(), "mysql") {
+		t.Errorf("err = %v, want the unknown backend named", err)
+	}
+}
+
+func TestOpenStorageDefaultsToPostgres(t *testing.T) {
+	// No DB URL: no storage, but no error either (proxy-only mode).
+	storage, err := openStorage(Config{})
+	if err != nil {
+		t.Fatalf("openStorage: %v", err)
+	}
+	if storage != nil {
+		t.Error("storage should be nil without a database URL")
+	}
+}
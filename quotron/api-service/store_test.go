@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func TestShouldStore(t *testing.T) {
+	tests := []struct {
+		name      string
+		autoStore bool
+		url       string
+		want      bool
+	}{
+		{"default on", true, "/api/quote/AAPL", true},
+		{"default off", false, "/api/quote/AAPL", false},
+		{"param overrides default on", true, "/api/quote/AAPL?store=false", false},
+		{"param overrides default off", false, "/api/quote/AAPL?store=true", true},
+		{"invalid param falls back to default", true, "/api/quote/AAPL?store=maybe", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{cfg: Config{AutoStore: tt.autoStore}}
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if got := s.shouldStore(req); got != tt.want {
+				t.Errorf("shouldStore(%s) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreFalseSkipsDBWrite(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		cfg:     Config{AutoStore: true},
+		storage: NewStorage(db, ConflictIgnore),
+	}
+	quote := &models.StockQuote{Symbol: "AAPL", Price: 1, Timestamp: time.Now(), Source: "mock"}
+
+	s.storeQuote(httptest.NewRequest(http.MethodGet, "/api/quote/AAPL?store=false", nil), quote)
+	s.flushWrites()
+	if got := queryCount("primary"); got != 0 {
+		t.Errorf("writes with ?store=false = %d, want 0", got)
+	}
+
+	s.storeQuote(httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil), quote)
+	s.flushWrites()
+	if got := queryCount("primary"); got != 1 {
+		t.Errorf("writes without the param = %d, want 1", got)
+	}
+}
+
+func TestHistoryFlagsDisabledAutoStore(t *testing.T) {
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		cfg:     Config{AutoStore: false},
+		storage: NewStorage(db, ConflictIgnore),
+	}
+
+	rec := httptest.NewRecorder()
+	s.getQuoteHandler(rec, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL/history", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Auto-Store"); got != "off" {
+		t.Errorf("X-Auto-Store = %q, want %q", got, "off")
+	}
+}
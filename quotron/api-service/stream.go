@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// quoteChannel is the Postgres NOTIFY channel the insert trigger fires
+// on (see storage/migrations/004_notify_quote_inserts.sql).
+const quoteChannel = "quotron_quotes"
+
+// bridgeRetryInterval is how long the bridge waits before redialing a
+// dropped LISTEN connection.
+const bridgeRetryInterval = 30 * time.Second
+
+// quoteBroker fans notification payloads out to the connected stream
+// clients. Slow clients drop messages rather than backing up the rest.
+type quoteBroker struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+func newQuoteBroker() *quoteBroker {
+	return &quoteBroker{clients: map[chan []byte]struct{}{}}
+}
+
+func (b *quoteBroker) subscribe() chan []byte {
+	ch := make(chan []byte, 16)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clients[ch] = struct{}{}
+	return ch
+}
+
+func (b *quoteBroker) unsubscribe(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, ch)
+}
+
+func (b *quoteBroker) broadcast(msg []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// streamHandler serves /api/stream: a WebSocket pushing every persisted
+// quote as JSON, straight from the Postgres insert trigger.
+func (s *Server) streamHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ch := s.broker.subscribe()
+	defer s.broker.unsubscribe(ch)
+
+	closed := make(chan struct{})
+	go wsDiscard(conn, closed)
+
+	for {
+		select {
+		case msg := <-ch:
+			if err := wsWriteText(conn, msg); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// startQuoteBridge LISTENs on the quote channel and feeds notifications
+// to the broker. Deployments without the trigger (or without a DB) log
+// once and run without the live stream — it is an optional data path for
+// setups not running Redis.
+func (s *Server) startQuoteBridge() {
+	if s.storage == nil || s.cfg.DatabaseURL == "" {
+		return
+	}
+	ok, err := s.storage.HasTrigger("stock_quotes_notify")
+	if err != nil {
+		log.Printf("WARNING: checking for quote notify trigger: %v; live quote stream disabled", err)
+		return
+	}
+	if !ok {
+		log.Printf("WARNING: stock_quotes notify trigger missing; live quote stream disabled (apply migration 004)")
+		return
+	}
+
+	go func() {
+		for {
+			// Guarded so a panic anywhere in the bridge (dial, decode,
+			// broadcast) degrades to a reconnect, not a dead stream.
+			guard("quote stream bridge", s.bridgeCycle)
+			time.Sleep(bridgeRetryInterval)
+		}
+	}()
+}
+
+// bridgeCycle runs one listener connection until it drops: dial, then
+// forward notifications to the broker. The caller sleeps and retries.
+func (s *Server) bridgeCycle() {
+	listener, err := dialPGListener(s.cfg.DatabaseURL, quoteChannel)
+	if err != nil {
+		log.Printf("WARNING: quote stream listener: %v; retrying in %s", err, bridgeRetryInterval)
+		return
+	}
+	log.Printf("streaming persisted quotes from Postgres channel %s", quoteChannel)
+	for payload := range listener.Notifications() {
+		s.broker.broadcast([]byte(payload))
+	}
+	log.Printf("WARNING: quote stream listener disconnected; reconnecting in %s", bridgeRetryInterval)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBrokerFansOut(t *testing.T) {
+	b := newQuoteBroker()
+	a := b.subscribe()
+	c := b.subscribe()
+	defer b.unsubscribe(a)
+	defer b.unsubscribe(c)
+
+	b.broadcast([]byte("tick"))
+
+	for _, ch := range []chan []byte{a, c} {
+		select {
+		case msg := <-ch:
+			if string(msg) != "tick" {
+				t.Errorf("got %q, want tick", msg)
+			}
+		default:
+			t.Error("subscriber did not receive the broadcast")
+		}
+	}
+}
+
+func TestBrokerDropsForSlowClients(t *testing.T) {
+	b := newQuoteBroker()
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+
+	// Overfill the buffer; broadcast must not block.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < cap(ch)+10; i++ {
+			b.broadcast([]byte("tick"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcast blocked on a full client buffer")
+	}
+}
+
+// dialStream opens a raw TCP connection to the test server and performs
+// the WebSocket handshake by hand.
+func dialStream(t *testing.T, ts *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(conn, "GET /api/stream HTTP/1.1\r\n"+
+		"Host: test\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	r := bufio.NewReader(conn)
+	status, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(status, "101") {
+		t.Fatalf("handshake status = %q, want 101", strings.TrimSpace(status))
+	}
+	var accept string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "Sec-WebSocket-Accept: "); ok {
+			accept = v
+		}
+	}
+	// Expected value for the sample nonce, straight from RFC 6455.
+	if accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q, want the RFC sample digest", accept)
+	}
+	return conn, r
+}
+
+func TestStreamPushesBroadcasts(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient(), broker: newQuoteBroker()}
+	ts := httptest.NewServer(http.HandlerFunc(s.streamHandler))
+	defer ts.Close()
+
+	conn, r := dialStream(t, ts)
+	defer conn.Close()
+
+	// Give the handler a moment to subscribe after the handshake.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		s.broker.broadcast([]byte(`{"symbol":"AAPL"}`))
+		conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		header := make([]byte, 2)
+		if _, err := r.Read(header[:1]); err == nil {
+			if header[0] != 0x81 {
+				t.Fatalf("frame type = %#x, want text (0x81)", header[0])
+			}
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			if _, err := io.ReadFull(r, header[1:]); err != nil {
+				t.Fatal(err)
+			}
+			payload := make([]byte, header[1])
+			if _, err := io.ReadFull(r, payload); err != nil {
+				t.Fatal(err)
+			}
+			if string(payload) != `{"symbol":"AAPL"}` {
+				t.Fatalf("payload = %q", payload)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no frame received")
+		}
+	}
+}
+
+func TestStreamRejectsPlainGET(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient(), broker: newQuoteBroker()}
+	rec := httptest.NewRecorder()
+	s.streamHandler(rec, httptest.NewRequest(http.MethodGet, "/api/stream", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 without upgrade headers", rec.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Pagination bounds for /api/symbols. The default page is big enough
+// for a typical deployment's whole universe; the cap keeps one request
+// from dragging the entire table through a group scan.
+const (
+	defaultSymbolPageSize = 100
+	maxSymbolPageSize     = 1000
+)
+
+// symbolsResponse is the envelope for /api/symbols. Limit and Offset
+// echo the page served so clients can walk the universe statelessly.
+type symbolsResponse struct {
+	Symbols []SymbolInfo `json:"symbols"`
+	Limit   int          `json:"limit"`
+	Offset  int          `json:"offset"`
+}
+
+// symbolsHandler serves GET /api/symbols: the distinct symbols we hold
+// quotes for, each with its quote count and latest timestamp, so
+// clients can discover coverage (and search boxes can autocomplete)
+// instead of probing symbols one by one. ?exchange= restricts the
+// universe to one venue; ?limit= and ?offset= page the result.
+func (s *Server) symbolsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.storage == nil {
+		writeError(w, http.StatusServiceUnavailable, "storage not available")
+		return
+	}
+
+	limit := defaultSymbolPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSymbolPageSize {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		offset = n
+	}
+	exchange := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("exchange")))
+
+	symbols, err := s.storage.ListSymbols(r.Context(), exchange, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list symbols: "+err.Error())
+		return
+	}
+	if symbols == nil {
+		symbols = []SymbolInfo{}
+	}
+	writeJSON(w, http.StatusOK, symbolsResponse{Symbols: symbols, Limit: limit, Offset: offset})
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// symbolDriver is a stub driver serving a fixed two-symbol universe and
+// recording the arguments of the last query, so tests can assert both
+// the response shape and the filter/pagination forwarding.
+type symbolDriver struct{}
+
+var symbolLog = struct {
+	sync.Mutex
+	args []driver.Value
+}{}
+
+func lastSymbolArgs() []driver.Value {
+	symbolLog.Lock()
+	defer symbolLog.Unlock()
+	return symbolLog.args
+}
+
+func (symbolDriver) Open(name string) (driver.Conn, error) { return symbolConn{}, nil }
+
+type symbolConn struct{}
+
+func (symbolConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (symbolConn) Close() error              { return nil }
+func (symbolConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (symbolConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	symbolLog.Lock()
+	defer symbolLog.Unlock()
+	symbolLog.args = nil
+	for _, arg := range args {
+		symbolLog.args = append(symbolLog.args, arg.Value)
+	}
+	return &symbolRows{}, nil
+}
+
+type symbolRows struct{ served int }
+
+func (r *symbolRows) Columns() []string { return []string{"symbol", "quotes", "latest_at"} }
+func (r *symbolRows) Close() error      { return nil }
+func (r *symbolRows) Next(dest []driver.Value) error {
+	universe := [][]driver.Value{
+		{"AAPL", int64(42), time.Date(2025, 6, 2, 15, 30, 0, 0, time.UTC)},
+		{"MSFT", int64(7), time.Date(2025, 6, 2, 15, 45, 0, 0, time.UTC)},
+	}
+	if r.served >= len(universe) {
+		return io.EOF
+	}
+	copy(dest, universe[r.served])
+	r.served++
+	return nil
+}
+
+func init() {
+	sql.Register("symboltest", symbolDriver{})
+}
+
+func symbolServer(t *testing.T) *Server {
+	t.Helper()
+	db, err := sql.Open("symboltest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{cfg: Config{Mock: true}, storage: NewStorage(db, ConflictIgnore)}
+}
+
+func getSymbols(s *Server, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	s.symbolsHandler(rec, req)
+	return rec
+}
+
+func TestSymbolsListsCoverage(t *testing.T) {
+	rec := getSymbols(symbolServer(t), "/api/symbols")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp symbolsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Symbols) != 2 {
+		t.Fatalf("symbols = %d, want 2", len(resp.Symbols))
+	}
+	if resp.Symbols[0].Symbol != "AAPL" || resp.Symbols[0].Quotes != 42 {
+		t.Errorf("first symbol = %+v, want AAPL with 42 quotes", resp.Symbols[0])
+	}
+	if resp.Symbols[0].LatestAt.IsZero() {
+		t.Error("symbols should carry their latest quote timestamp")
+	}
+	if resp.Limit != defaultSymbolPageSize || resp.Offset != 0 {
+		t.Errorf("page = (%d, %d), want the defaults echoed", resp.Limit, resp.Offset)
+	}
+}
+
+func TestSymbolsForwardsFilterAndPagination(t *testing.T) {
+	rec := getSymbols(symbolServer(t), "/api/symbols?exchange=nasdaq&limit=10&offset=20")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	args := lastSymbolArgs()
+	if len(args) != 3 {
+		t.Fatalf("query args = %v, want exchange, limit, offset", args)
+	}
+	if args[0] != "NASDAQ" {
+		t.Errorf("exchange arg = %v, want the filter upcased", args[0])
+	}
+	if args[1] != int64(10) || args[2] != int64(20) {
+		t.Errorf("limit/offset args = %v/%v, want 10/20", args[1], args[2])
+	}
+}
+
+func TestSymbolsRejectsBadPagination(t *testing.T) {
+	s := symbolServer(t)
+	for _, target := range []string{
+		"/api/symbols?limit=0",
+		"/api/symbols?limit=9999",
+		"/api/symbols?limit=ten",
+		"/api/symbols?offset=-1",
+	} {
+		if rec := getSymbols(s, target); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestSymbolsWithoutStorageIs503(t *testing.T) {
+	s := &Server{cfg: Config{Mock: true}}
+	if rec := getSymbols(s, "/api/symbols"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
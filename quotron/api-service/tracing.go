@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// tracingMiddleware opens a span per request, resuming the caller's
+// trace when the request carries a traceparent header. Handlers start
+// child spans around upstream fetches and DB writes via the request
+// context.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := trace.Extract(r.Context(), r.Header)
+		ctx, span := trace.StartSpan(ctx, r.Method+" "+r.URL.Path)
+		defer span.End()
+
+		span.SetAttr("http.method", r.Method)
+		span.SetAttr("http.path", r.URL.Path)
+		if id := RequestID(ctx); id != "" {
+			span.SetAttr("request.id", id)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// recordingExporter collects finished spans for assertions.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*trace.Span
+}
+
+func (e *recordingExporter) ExportSpan(s *trace.Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+func (e *recordingExporter) named(name string) *trace.Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range e.spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestHandledRequestProducesSpans(t *testing.T) {
+	rec := &recordingExporter{}
+	trace.SetExporter(rec)
+	defer trace.SetExporter(nil)
+
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient()}
+	handler := requestIDMiddleware(tracingMiddleware(s.setupRoutes()))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	root := rec.named("GET /api/quote/AAPL")
+	if root == nil {
+		t.Fatalf("no request span exported; got %+v", rec.spans)
+	}
+	if root.Attrs["request.id"] == "" {
+		t.Error("request span should carry the request ID")
+	}
+	fetch := rec.named("upstream.GetStockQuote")
+	if fetch == nil {
+		t.Fatal("no span around the upstream fetch")
+	}
+	if fetch.TraceID != root.TraceID || fetch.ParentID != root.SpanID {
+		t.Errorf("fetch span (%s/%s) should be a child of the request span (%s/%s)",
+			fetch.TraceID, fetch.ParentID, root.TraceID, root.SpanID)
+	}
+}
+
+func TestIncomingTraceparentIsResumed(t *testing.T) {
+	rec := &recordingExporter{}
+	trace.SetExporter(rec)
+	defer trace.SetExporter(nil)
+
+	s := &Server{cfg: Config{Mock: true}, client: NewMockDataClient()}
+	handler := tracingMiddleware(s.setupRoutes())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/quote/AAPL", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	root := rec.named("GET /api/quote/AAPL")
+	if root == nil {
+		t.Fatal("no request span exported")
+	}
+	if root.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the propagated one", root.TraceID)
+	}
+	if root.ParentID != "00f067aa0ba902b7" {
+		t.Errorf("parent span = %s, want the caller's", root.ParentID)
+	}
+}
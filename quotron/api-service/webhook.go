@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// maxWebhookBytes caps a pushed payload; providers sending more than
+// this are misbehaving.
+const maxWebhookBytes = 1 << 20 // 1 MiB
+
+// webhookSignatureHeader carries the hex HMAC-SHA256 of the raw body,
+// optionally prefixed "sha256=" (the convention GitHub popularized).
+const webhookSignatureHeader = "X-Signature"
+
+// webhookMapper converts one provider's payload into quotes. Custom
+// providers register a mapper under their source name; sources without
+// one get the generic mapper, which expects our own quote JSON.
+type webhookMapper func(body []byte) ([]*models.StockQuote, error)
+
+var webhookMappersMu sync.RWMutex
+var webhookMappers = map[string]webhookMapper{}
+
+// registerWebhookMapper makes a payload mapper available for a source,
+// so proprietary push formats plug in without touching the handler.
+func registerWebhookMapper(source string, m webhookMapper) {
+	webhookMappersMu.Lock()
+	defer webhookMappersMu.Unlock()
+	webhookMappers[source] = m
+}
+
+func mapperFor(source string) webhookMapper {
+	webhookMappersMu.RLock()
+	defer webhookMappersMu.RUnlock()
+	if m, ok := webhookMappers[source]; ok {
+		return m
+	}
+	return genericWebhookMapper
+}
+
+// genericWebhookMapper decodes {"quotes": [...]} in our own model shape.
+func genericWebhookMapper(body []byte) ([]*models.StockQuote, error) {
+	var payload struct {
+		Quotes []*models.StockQuote `json:"quotes"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.Quotes) == 0 {
+		return nil, fmt.Errorf("payload has no quotes")
+	}
+	return payload.Quotes, nil
+}
+
+// verifyWebhookSignature checks the hex HMAC-SHA256 of body against the
+// source's shared secret, in constant time.
+func verifyWebhookSignature(secret string, body []byte, signature string) bool {
+	signature = strings.TrimPrefix(signature, "sha256=")
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// webhookIngestHandler serves POST /api/ingest/webhook/{source}: verify
+// the per-source HMAC, map the payload, and feed the quotes to the
+// write-back pipeline. Unknown sources 404 so the endpoint does not
+// confirm which providers are configured.
+func (s *Server) webhookIngestHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	source := strings.TrimPrefix(r.URL.Path, "/api/ingest/webhook/")
+	secret, ok := s.cfg.WebhookSecrets[source]
+	if !ok || source == "" {
+		writeError(w, http.StatusNotFound, "unknown webhook source")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading body: "+err.Error())
+		return
+	}
+	if !verifyWebhookSignature(secret, body, r.Header.Get(webhookSignatureHeader)) {
+		reqLog(r.Context(), "WARNING: webhook signature mismatch for source %s", source)
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	quotes, err := mapperFor(source)(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "mapping payload: "+err.Error())
+		return
+	}
+
+	accepted := 0
+	for _, quote := range quotes {
+		if quote.Source == "" {
+			quote.Source = source
+		}
+		normalized, err := models.NormalizeSource(quote.Source)
+		if err != nil {
+			reqLog(r.Context(), "WARNING: dropping webhook quote for %s: %v", quote.Symbol, err)
+			continue
+		}
+		quote.Source = string(normalized)
+		if s.storage != nil {
+			s.enqueueQuote(quote)
+		}
+		accepted++
+	}
+	writeJSON(w, http.StatusAccepted, map[string]int{"accepted": accepted})
+}
+
+// parseWebhookSecrets parses the -webhook-secrets flag value:
+// comma-separated source=secret pairs.
+func parseWebhookSecrets(value string) (map[string]string, error) {
+	secrets := map[string]string{}
+	for _, pair := range splitList(value) {
+		source, secret, ok := strings.Cut(pair, "=")
+		if !ok || source == "" || secret == "" {
+			return nil, fmt.Errorf("invalid webhook secret %q (want source=secret)", pair)
+		}
+		secrets[source] = secret
+	}
+	return secrets, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func signBody(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookServer(t *testing.T) *Server {
+	t.Helper()
+	resetRouteLog()
+	db, err := sql.Open("routetest", "primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{
+		cfg:     Config{WebhookSecrets: map[string]string{"acme": "s3cret"}},
+		storage: NewStorage(db, ConflictIgnore),
+	}
+}
+
+func postWebhook(s *Server, source, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/ingest/webhook/"+source, strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(webhookSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	s.webhookIngestHandler(rec, req)
+	return rec
+}
+
+const acmeBody = `{"quotes":[{"symbol":"AAPL","price":190.5,"timestamp":"2026-08-27T14:30:00Z","source":"manual"}]}`
+
+func TestWebhookAcceptsValidSignature(t *testing.T) {
+	s := webhookServer(t)
+	rec := postWebhook(s, "acme", acmeBody, signBody("s3cret", acmeBody))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["accepted"] != 1 {
+		t.Errorf("accepted = %d, want 1", resp["accepted"])
+	}
+	s.flushWrites()
+	if got := queryCount("primary"); got != 1 {
+		t.Errorf("stored quotes = %d, want 1", got)
+	}
+}
+
+func TestWebhookRejectsBadSignature(t *testing.T) {
+	s := webhookServer(t)
+	for name, signature := range map[string]string{
+		"wrong secret": signBody("other", acmeBody),
+		"missing":      "",
+		"garbage":      "sha256=zzzz",
+	} {
+		if rec := postWebhook(s, "acme", acmeBody, signature); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s signature: status = %d, want 401", name, rec.Code)
+		}
+	}
+	s.flushWrites()
+	if got := queryCount("primary"); got != 0 {
+		t.Errorf("stored quotes = %d, want 0 after rejected pushes", got)
+	}
+}
+
+func TestWebhookUnknownSourceIs404(t *testing.T) {
+	s := webhookServer(t)
+	if rec := postWebhook(s, "globex", acmeBody, signBody("s3cret", acmeBody)); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 for an unconfigured source", rec.Code)
+	}
+}
+
+func TestWebhookCustomMapper(t *testing.T) {
+	// A provider-specific payload shape, translated by a registered mapper.
+	registerWebhookMapper("vendorx", func(body []byte) ([]*models.StockQuote, error) {
+		var payload struct {
+			Ticker string  `json:"ticker"`
+			Last   float64 `json:"last"`
+			At     string  `json:"at"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		at, err := time.Parse(time.RFC3339, payload.At)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.StockQuote{{Symbol: payload.Ticker, Price: payload.Last, Timestamp: at, Source: "manual"}}, nil
+	})
+
+	s := webhookServer(t)
+	s.cfg.WebhookSecrets["vendorx"] = "hunter2"
+
+	body := `{"ticker":"MSFT","last":411.25,"at":"2026-08-27T15:00:00Z"}`
+	rec := postWebhook(s, "vendorx", body, signBody("hunter2", body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	s.flushWrites()
+	if got := queryCount("primary"); got != 1 {
+		t.Errorf("stored quotes = %d, want 1 via the custom mapper", got)
+	}
+}
+
+func TestWebhookRejectsUnmappablePayload(t *testing.T) {
+	s := webhookServer(t)
+	body := `{"nothing":"useful"}`
+	if rec := postWebhook(s, "acme", body, signBody("s3cret", body)); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 for an unmappable payload", rec.Code)
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// webhookFieldMap says where one model field comes from in a provider's
+// payload: From is a dot-separated path into the quote object, Transform
+// an optional conversion applied to the raw value.
+type webhookFieldMap struct {
+	From      string `json:"from"`
+	Transform string `json:"transform,omitempty"`
+}
+
+// webhookMapping is the declarative description of one provider's push
+// format, loaded from the -webhook-mappings file. It compiles into a
+// webhookMapper, so new providers onboard with configuration instead of
+// a code change.
+type webhookMapping struct {
+	// QuotesPath locates the array of quote objects in the payload,
+	// dot-separated ("data.items"). Empty means the payload is a
+	// single quote object.
+	QuotesPath string `json:"quotes_path,omitempty"`
+	// Fields maps model field names (symbol, price, change,
+	// change_percent, volume, timestamp, exchange, source) to their
+	// provider-side locations.
+	Fields map[string]webhookFieldMap `json:"fields"`
+	// Defaults sets model fields the provider does not send, e.g.
+	// {"source": "manual"}.
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// quoteFields are the model field names a mapping may set.
+var quoteFields = map[string]bool{
+	"symbol": true, "price": true, "change": true, "change_percent": true,
+	"volume": true, "timestamp": true, "exchange": true, "source": true,
+}
+
+// webhookTransforms are the supported value conversions.
+var webhookTransforms = map[string]bool{
+	"upper": true, "lower": true, "unix": true, "unix_ms": true,
+}
+
+// validate rejects mappings referring to unknown fields or transforms,
+// so a typo in the config file fails at startup instead of dropping
+// payloads at 3 AM.
+func (m webhookMapping) validate() error {
+	if len(m.Fields) == 0 {
+		return fmt.Errorf("mapping has no fields")
+	}
+	for field, fm := range m.Fields {
+		if !quoteFields[field] {
+			return fmt.Errorf("unknown quote field %q", field)
+		}
+		if fm.From == "" {
+			return fmt.Errorf("field %q has no source path", field)
+		}
+		if fm.Transform != "" && !webhookTransforms[fm.Transform] {
+			return fmt.Errorf("field %q: unknown transform %q", field, fm.Transform)
+		}
+	}
+	for field := range m.Defaults {
+		if !quoteFields[field] {
+			return fmt.Errorf("unknown quote field %q in defaults", field)
+		}
+	}
+	return nil
+}
+
+// mapper compiles the mapping into a webhookMapper.
+func (m webhookMapping) mapper() webhookMapper {
+	return func(body []byte) ([]*models.StockQuote, error) {
+		var payload interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+
+		var objects []interface{}
+		if m.QuotesPath == "" {
+			objects = []interface{}{payload}
+		} else {
+			located, err := lookupPath(payload, m.QuotesPath)
+			if err != nil {
+				return nil, err
+			}
+			list, ok := located.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s is not an array", m.QuotesPath)
+			}
+			objects = list
+		}
+		if len(objects) == 0 {
+			return nil, fmt.Errorf("payload has no quotes")
+		}
+
+		quotes := make([]*models.StockQuote, 0, len(objects))
+		for i, obj := range objects {
+			quote := &models.StockQuote{}
+			for field, value := range m.Defaults {
+				if err := setQuoteField(quote, field, value); err != nil {
+					return nil, fmt.Errorf("quote %d: default %s: %w", i, field, err)
+				}
+			}
+			for field, fm := range m.Fields {
+				raw, err := lookupPath(obj, fm.From)
+				if err != nil {
+					return nil, fmt.Errorf("quote %d: field %s: %w", i, field, err)
+				}
+				value, err := applyTransform(fm.Transform, raw)
+				if err != nil {
+					return nil, fmt.Errorf("quote %d: field %s: %w", i, field, err)
+				}
+				if err := setQuoteField(quote, field, value); err != nil {
+					return nil, fmt.Errorf("quote %d: field %s: %w", i, field, err)
+				}
+			}
+			quotes = append(quotes, quote)
+		}
+		return quotes, nil
+	}
+}
+
+// lookupPath walks a dot-separated path through decoded JSON objects.
+func lookupPath(value interface{}, path string) (interface{}, error) {
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: not an object", path)
+		}
+		if value, ok = obj[key]; !ok {
+			return nil, fmt.Errorf("%s: missing key %q", path, key)
+		}
+	}
+	return value, nil
+}
+
+// applyTransform converts a raw payload value. The zero transform passes
+// the value through untouched.
+func applyTransform(transform string, value interface{}) (interface{}, error) {
+	switch transform {
+	case "":
+		return value, nil
+	case "upper", "lower":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s needs a string, got %T", transform, value)
+		}
+		if transform == "upper" {
+			return strings.ToUpper(s), nil
+		}
+		return strings.ToLower(s), nil
+	case "unix", "unix_ms":
+		n, ok := value.(float64) // JSON numbers decode as float64
+		if !ok {
+			return nil, fmt.Errorf("%s needs a number, got %T", transform, value)
+		}
+		if transform == "unix_ms" {
+			return time.UnixMilli(int64(n)).UTC(), nil
+		}
+		return time.Unix(int64(n), 0).UTC(), nil
+	}
+	return nil, fmt.Errorf("unknown transform %q", transform)
+}
+
+// setQuoteField assigns one mapped value onto the model, coercing the
+// loose JSON types (and string defaults) to the field's Go type.
+func setQuoteField(q *models.StockQuote, field string, value interface{}) error {
+	switch field {
+	case "symbol":
+		return setString(&q.Symbol, value)
+	case "exchange":
+		return setString(&q.Exchange, value)
+	case "source":
+		return setString(&q.Source, value)
+	case "price":
+		return setFloat(&q.Price, value)
+	case "change":
+		return setFloat(&q.Change, value)
+	case "change_percent":
+		return setFloat(&q.ChangePercent, value)
+	case "volume":
+		var f float64
+		if err := setFloat(&f, value); err != nil {
+			return err
+		}
+		q.Volume = models.Int64(int64(f))
+		return nil
+	case "timestamp":
+		switch v := value.(type) {
+		case time.Time: // already converted by unix/unix_ms
+			q.Timestamp = v
+			return nil
+		case string:
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return err
+			}
+			q.Timestamp = ts
+			return nil
+		}
+		return fmt.Errorf("timestamp needs an RFC3339 string or a unix transform, got %T", value)
+	}
+	return fmt.Errorf("unknown quote field %q", field)
+}
+
+func setString(dst *string, value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("want a string, got %T", value)
+	}
+	*dst = s
+	return nil
+}
+
+func setFloat(dst *float64, value interface{}) error {
+	switch v := value.(type) {
+	case float64:
+		*dst = v
+		return nil
+	case string: // defaults arrive as strings
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*dst = f
+		return nil
+	}
+	return fmt.Errorf("want a number, got %T", value)
+}
+
+// loadWebhookMappings reads a JSON file of {source: mapping} and
+// registers a compiled mapper for each source.
+func loadWebhookMappings(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var mappings map[string]webhookMapping
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for source, mapping := range mappings {
+		if err := mapping.validate(); err != nil {
+			return fmt.Errorf("%s: mapping for %s: %w", path, source, err)
+		}
+		registerWebhookMapper(source, mapping.mapper())
+	}
+	return nil
+}
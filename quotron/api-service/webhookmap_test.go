@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vendorMapping describes a made-up provider: nested quote array,
+// lowercase tickers, epoch-millisecond timestamps, prices under odd
+// names.
+const vendorMapping = `{
+	"quotes_path": "data.items",
+	"fields": {
+		"symbol":    {"from": "ticker", "transform": "upper"},
+		"price":     {"from": "pricing.last"},
+		"volume":    {"from": "vol"},
+		"timestamp": {"from": "ts", "transform": "unix_ms"}
+	},
+	"defaults": {"source": "manual", "exchange": "NASDAQ"}
+}`
+
+const vendorPayload = `{
+	"data": {
+		"items": [
+			{"ticker": "aapl", "pricing": {"last": 190.5}, "vol": 1200, "ts": 1756305000000},
+			{"ticker": "msft", "pricing": {"last": 411.25}, "vol": 900, "ts": 1756305060000}
+		]
+	}
+}`
+
+func vendorMapper(t *testing.T) webhookMapper {
+	t.Helper()
+	var mapping webhookMapping
+	if err := json.Unmarshal([]byte(vendorMapping), &mapping); err != nil {
+		t.Fatal(err)
+	}
+	if err := mapping.validate(); err != nil {
+		t.Fatal(err)
+	}
+	return mapping.mapper()
+}
+
+func TestDeclarativeMapperTranslatesPayload(t *testing.T) {
+	quotes, err := vendorMapper(t)(([]byte)(vendorPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotes) != 2 {
+		t.Fatalf("got %d quotes, want 2", len(quotes))
+	}
+
+	q := quotes[0]
+	if q.Symbol != "AAPL" {
+		t.Errorf("Symbol = %q, want AAPL (upper transform)", q.Symbol)
+	}
+	if q.Price != 190.5 {
+		t.Errorf("Price = %v, want 190.5", q.Price)
+	}
+	if q.Volume == nil || *q.Volume != 1200 {
+		t.Errorf("Volume = %v, want 1200", q.Volume)
+	}
+	if want := time.UnixMilli(1756305000000).UTC(); !q.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", q.Timestamp, want)
+	}
+	if q.Source != "manual" || q.Exchange != "NASDAQ" {
+		t.Errorf("defaults not applied: source %q exchange %q", q.Source, q.Exchange)
+	}
+}
+
+func TestDeclarativeMapperRejectsMissingField(t *testing.T) {
+	body := `{"data": {"items": [{"ticker": "aapl"}]}}`
+	if _, err := vendorMapper(t)([]byte(body)); err == nil {
+		t.Error("payload without mapped fields should fail")
+	} else if !strings.Contains(err.Error(), "missing key") {
+		t.Errorf("error should name the missing path, got: %v", err)
+	}
+}
+
+func TestMappingValidation(t *testing.T) {
+	cases := map[string]webhookMapping{
+		"no fields":         {},
+		"unknown field":     {Fields: map[string]webhookFieldMap{"ticker": {From: "t"}}},
+		"empty path":        {Fields: map[string]webhookFieldMap{"symbol": {}}},
+		"unknown transform": {Fields: map[string]webhookFieldMap{"symbol": {From: "t", Transform: "reverse"}}},
+		"unknown default": {
+			Fields:   map[string]webhookFieldMap{"symbol": {From: "t"}},
+			Defaults: map[string]string{"venue": "NASDAQ"},
+		},
+	}
+	for name, mapping := range cases {
+		if err := mapping.validate(); err == nil {
+			t.Errorf("%s: validate should fail", name)
+		}
+	}
+}
+
+// Loading a mapping file registers mappers the webhook handler uses
+// end to end.
+func TestLoadedMappingServesWebhook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.json")
+	if err := os.WriteFile(path, []byte(`{"vendory": `+vendorMapping+`}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadWebhookMappings(path); err != nil {
+		t.Fatal(err)
+	}
+
+	s := webhookServer(t)
+	s.cfg.WebhookSecrets["vendory"] = "hunter2"
+	rec := postWebhook(s, "vendory", vendorPayload, signBody("hunter2", vendorPayload))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	s.flushWrites()
+	if got := queryCount("primary"); got != 2 {
+		t.Errorf("stored quotes = %d, want 2", got)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+	"github.com/we-be/tiny-ria/quotron/trace"
+)
+
+// storeQueueSize bounds the write-back queue. A full queue drops quotes
+// (with a warning) rather than stalling request handlers — write-back is
+// opportunistic caching, not the system of record for these fetches.
+const storeQueueSize = 256
+
+// flushSentinel is a marker value the writer answers on the flushed
+// channel instead of storing, so tests can wait for the queue to drain.
+var flushSentinel = &models.StockQuote{}
+
+// enqueueQuote hands a validated quote to the background writer, so a
+// batch of N symbols costs N channel sends instead of N concurrent DB
+// writes on the request path.
+func (s *Server) enqueueQuote(quote *models.StockQuote) {
+	s.storeOnce.Do(s.startWriter)
+	select {
+	case s.storeCh <- quote:
+	default:
+		log.Printf("WARNING: write-back queue full, dropping quote for %s", quote.Symbol)
+	}
+}
+
+func (s *Server) startWriter() {
+	s.storeCh = make(chan *models.StockQuote, storeQueueSize)
+	s.flushed = make(chan struct{})
+	go s.storeWriter()
+}
+
+// storeWriter is the single goroutine draining the write-back queue.
+// Requests that enqueued a quote may be long gone, so writes run under a
+// background context (still bounded by the storage query timeout).
+func (s *Server) storeWriter() {
+	for quote := range s.storeCh {
+		if quote == flushSentinel {
+			s.flushed <- struct{}{}
+			continue
+		}
+		// Guarded per write: a panic here would kill the only writer and
+		// silently drop everything enqueued after it.
+		guard("write-back writer", func() { s.writeQuote(quote) })
+	}
+}
+
+func (s *Server) writeQuote(quote *models.StockQuote) {
+	ctx, span := trace.StartSpan(context.Background(), "db.InsertQuote")
+	span.SetAttr("symbol", quote.Symbol)
+	err := s.storage.InsertQuote(ctx, quote)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		log.Printf("WARNING: failed to store quote for %s: %v", quote.Symbol, err)
+	}
+}
+
+// flushWrites blocks until every quote enqueued so far has been written,
+// for tests and benchmarks that need the queue drained.
+func (s *Server) flushWrites() {
+	s.storeOnce.Do(s.startWriter)
+	s.storeCh <- flushSentinel
+	<-s.flushed
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// benchQuotes builds one batch worth of storable quotes.
+func benchQuotes(n int) []*models.StockQuote {
+	quotes := make([]*models.StockQuote, n)
+	for i := range quotes {
+		quotes[i] = &models.StockQuote{Symbol: "AAPL", Price: 100, Timestamp: time.Now(), Source: "mock"}
+	}
+	return quotes
+}
+
+// BenchmarkBatchWriteFanOut compares the old write-back shape (one
+// goroutine per quote, joined before the response) against the single
+// channel-fed writer: the request path now pays a channel send instead
+// of a goroutine spawn plus a DB round-trip.
+func BenchmarkBatchWriteFanOut(b *testing.B) {
+	db, err := sql.Open("routetest", "bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	storage := NewStorage(db, ConflictIgnore)
+	quotes := benchQuotes(10)
+
+	b.Run("goroutine-per-write", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var wg sync.WaitGroup
+			for _, quote := range quotes {
+				wg.Add(1)
+				go func(q *models.StockQuote) {
+					defer wg.Done()
+					storage.InsertQuote(context.Background(), q)
+				}(quote)
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("single-writer", func(b *testing.B) {
+		s := &Server{cfg: Config{AutoStore: true}, storage: storage}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, quote := range quotes {
+				s.enqueueQuote(quote)
+			}
+			s.flushWrites()
+		}
+	})
+}
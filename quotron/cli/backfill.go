@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/client"
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// backfillSource marks rows inserted by the backfill command so they are
+// distinguishable from live polled quotes.
+const backfillSource = "backfill"
+
+// queryTimeout bounds each individual DB query so one hung connection
+// cannot stall the whole run. Set from --query-timeout.
+var queryTimeout = 5 * time.Second
+
+// queryCtx derives the bounded context a single query runs under.
+func queryCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// runBackfill implements `quotron backfill --symbols AAPL,MSFT --days 90`:
+// fetch daily history from the upstream proxy and bulk-insert it into
+// stock_quotes, skipping dates that already have data so reruns are
+// idempotent.
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	symbolsArg := fs.String("symbols", "", "comma-separated symbols to backfill (required)")
+	days := fs.Int("days", 90, "how many days of history to fetch")
+	dbURL := fs.String("db-url", defaultDBURL(), "Postgres connection string")
+	proxyURL := fs.String("proxy-url", "http://localhost:8001", "upstream quote proxy base URL")
+	timeout := fs.Duration("query-timeout", queryTimeout, "per-query database timeout (negative = unlimited)")
+	var importCfg ImportConfig
+	fs.IntVar(&importCfg.Concurrency, "concurrency", 1, "symbols imported in parallel")
+	fs.DurationVar(&importCfg.SymbolTimeout, "symbol-timeout", 0, "timeout per attempt at one symbol (0 = unlimited)")
+	fs.IntVar(&importCfg.Retries, "retries", 0, "extra attempts for a failed symbol")
+	fs.DurationVar(&importCfg.RetryDelay, "retry-delay", 2*time.Second, "pause before each retry")
+	manifestPath := fs.String("manifest", "", "manifest file for resumable imports; reruns skip symbols recorded there")
+	fs.Parse(args)
+	queryTimeout = *timeout
+
+	if *symbolsArg == "" {
+		return fmt.Errorf("--symbols is required")
+	}
+	if *dbURL == "" {
+		return fmt.Errorf("--db-url or DATABASE_URL is required")
+	}
+	if *days < 1 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	db, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+
+	proxy := client.NewProxyClient(*proxyURL)
+
+	var symbols []string
+	for _, symbol := range strings.Split(*symbolsArg, ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	var fn importFunc = func(ctx context.Context, symbol string) error {
+		inserted, skipped, err := backfillSymbol(ctx, db, proxy, symbol, *days)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: inserted %d, skipped %d (already present)\n", symbol, inserted, skipped)
+		return nil
+	}
+	if *manifestPath != "" {
+		manifest, err := loadManifest(*manifestPath)
+		if err != nil {
+			return err
+		}
+		var done []string
+		done, symbols, fn = resumeImport(manifest, symbols, fn)
+		for _, symbol := range done {
+			fmt.Printf("%s: already imported, skipping (manifest)\n", symbol)
+		}
+	}
+
+	failed := importSymbols(context.Background(), importCfg, symbols, fn)
+	if len(failed) > 0 {
+		for symbol, err := range failed {
+			fmt.Printf("%s: %v\n", symbol, err)
+		}
+		return fmt.Errorf("%d of %d symbols failed", len(failed), len(symbols))
+	}
+	return nil
+}
+
+// backfillSymbol fetches history for one symbol and inserts the days not
+// already covered by existing rows.
+func backfillSymbol(ctx context.Context, db *sql.DB, proxy *client.ProxyClient, symbol string, days int) (inserted, skipped int, err error) {
+	history, err := proxy.GetQuoteHistory(ctx, symbol, days)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fetching history: %w", err)
+	}
+
+	existing, err := existingDates(ctx, db, symbol, days)
+	if err != nil {
+		return 0, 0, fmt.Errorf("loading existing dates: %w", err)
+	}
+
+	for _, quote := range history {
+		day := quote.Timestamp.UTC().Format("2006-01-02")
+		if existing[day] {
+			skipped++
+			continue
+		}
+		if err := insertBackfillQuote(ctx, db, symbol, quote); err != nil {
+			return inserted, skipped, fmt.Errorf("inserting %s: %w", day, err)
+		}
+		existing[day] = true
+		inserted++
+	}
+	return inserted, skipped, nil
+}
+
+// existingDates returns the set of days (UTC, YYYY-MM-DD) that already
+// have at least one stored quote for the symbol in the window.
+func existingDates(ctx context.Context, db *sql.DB, symbol string, days int) (map[string]bool, error) {
+	ctx, cancel := queryCtx(ctx)
+	defer cancel()
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT to_char(timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD')
+		FROM stock_quotes
+		WHERE symbol = $1 AND timestamp >= $2`, symbol, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dates := map[string]bool{}
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		dates[day] = true
+	}
+	return dates, rows.Err()
+}
+
+func insertBackfillQuote(ctx context.Context, db *sql.DB, symbol string, q models.StockQuote) error {
+	ctx, cancel := queryCtx(ctx)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO stock_quotes (symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		symbol, q.Price, q.Change, q.ChangePercent, q.Volume, q.High24h, q.Low24h,
+		q.Timestamp, q.Exchange, backfillSource)
+	return err
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os"
+
+	"github.com/we-be/tiny-ria/quotron/db"
+)
+
+// defaultDBURL prefers an explicit DATABASE_URL, then the discrete DB_*
+// variables via the shared DSN builder.
+func defaultDBURL() string {
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		return v
+	}
+	return db.BuildDSNFromEnv()
+}
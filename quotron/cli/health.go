@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/redis"
+	"github.com/we-be/tiny-ria/quotron/scheduler"
+)
+
+// streamNotifier delivers job health transitions to a Redis stream, so
+// the agent (or anything tailing the stream) can alert on them.
+type streamNotifier struct {
+	conn   *redis.Conn
+	stream string
+}
+
+func (n *streamNotifier) Notify(ctx context.Context, ev scheduler.HealthEvent) error {
+	n.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err := n.conn.Do(healthEventCommand(n.stream, ev)...)
+	return err
+}
+
+// healthEventCommand builds the XADD adding one health event to the
+// stream, with field names matching HealthEvent's JSON tags.
+func healthEventCommand(stream string, ev scheduler.HealthEvent) []string {
+	return []string{
+		"XADD", stream, "*",
+		"job", ev.Job,
+		"from", string(ev.From),
+		"to", string(ev.To),
+		"consecutive_failures", strconv.Itoa(ev.Failures),
+		"at", ev.At.Format(time.RFC3339),
+	}
+}
+
+// buildHealthNotifier resolves the health-sink flags: a webhook URL, a
+// Redis stream, or neither (alerting stays off). Asking for both is an
+// error — one event, one sink.
+func buildHealthNotifier(webhook, redisAddr, stream string) (scheduler.HealthNotifier, func(), error) {
+	switch {
+	case webhook != "" && redisAddr != "":
+		return nil, nil, fmt.Errorf("--health-webhook and --health-redis are mutually exclusive")
+	case webhook != "":
+		return scheduler.NewWebhookNotifier(webhook), func() {}, nil
+	case redisAddr != "":
+		conn, err := redis.Dial(redisAddr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting to redis for health events: %w", err)
+		}
+		return &streamNotifier{conn: conn, stream: stream}, func() { conn.Close() }, nil
+	}
+	return nil, func() {}, nil
+}
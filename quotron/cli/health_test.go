@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/scheduler"
+)
+
+func TestHealthEventCommandFields(t *testing.T) {
+	at := time.Date(2026, 8, 27, 9, 30, 0, 0, time.UTC)
+	ev := scheduler.HealthEvent{
+		Job:      "stocks",
+		From:     scheduler.StateDegraded,
+		To:       scheduler.StateFailed,
+		Failures: 3,
+		At:       at,
+	}
+
+	got := strings.Join(healthEventCommand("quotron:health:events", ev), " ")
+	want := "XADD quotron:health:events * job stocks from degraded to failed consecutive_failures 3 at 2026-08-27T09:30:00Z"
+	if got != want {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+}
+
+func TestBuildHealthNotifierRejectsTwoSinks(t *testing.T) {
+	if _, _, err := buildHealthNotifier("http://hook", "localhost:6379", "s"); err == nil {
+		t.Fatal("want an error when both sinks are configured")
+	}
+}
+
+func TestBuildHealthNotifierOffByDefault(t *testing.T) {
+	n, closeNotifier, err := buildHealthNotifier("", "", "s")
+	if err != nil || n != nil {
+		t.Fatalf("notifier = %v, err = %v; want alerting off", n, err)
+	}
+	closeNotifier()
+}
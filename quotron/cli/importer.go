@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ImportConfig tunes how an import walks its symbol set. The zero value
+// means sequential, unbounded, no retries — the historical behavior.
+type ImportConfig struct {
+	// Concurrency is how many symbols are imported in parallel; values
+	// below 1 mean 1.
+	Concurrency int
+	// SymbolTimeout bounds each attempt at one symbol; 0 or negative
+	// means no limit.
+	SymbolTimeout time.Duration
+	// Retries is how many extra attempts a failed symbol gets.
+	Retries int
+	// RetryDelay is the pause before each retry.
+	RetryDelay time.Duration
+}
+
+// importFunc imports one symbol under the given context.
+type importFunc func(ctx context.Context, symbol string) error
+
+// importSymbols runs fn once per symbol under the config's concurrency,
+// per-attempt timeout, and retry policy. It returns the symbols that
+// still failed after retries; an empty map is full success.
+func importSymbols(ctx context.Context, cfg ImportConfig, symbols []string, fn importFunc) map[string]error {
+	workers := cfg.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		failed = map[string]error{}
+		wg     sync.WaitGroup
+		work   = make(chan string)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range work {
+				if err := importOne(ctx, cfg, symbol, fn); err != nil {
+					mu.Lock()
+					failed[symbol] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, symbol := range symbols {
+		work <- symbol
+	}
+	close(work)
+	wg.Wait()
+	return failed
+}
+
+// importOne attempts one symbol up to 1+Retries times, each attempt
+// under its own timeout. A dead parent context stops the retry loop.
+func importOne(ctx context.Context, cfg ImportConfig, symbol string, fn importFunc) error {
+	var err error
+	for attempt := 0; attempt <= cfg.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(cfg.RetryDelay):
+			}
+		}
+		attemptCtx, cancel := attemptContext(ctx, cfg.SymbolTimeout)
+		err = fn(attemptCtx, symbol)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	if cfg.Retries > 0 {
+		return fmt.Errorf("after %d attempts: %w", cfg.Retries+1, err)
+	}
+	return err
+}
+
+func attemptContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestImportConcurrencyIsCapped(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	fn := func(ctx context.Context, symbol string) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	}
+
+	symbols := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	failed := importSymbols(context.Background(), ImportConfig{Concurrency: 3}, symbols, fn)
+	if len(failed) != 0 {
+		t.Fatalf("failures = %v, want none", failed)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+	if peak < 2 {
+		t.Errorf("peak concurrency = %d, want parallel execution", peak)
+	}
+}
+
+func TestImportRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	fn := func(ctx context.Context, symbol string) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}
+
+	cfg := ImportConfig{Retries: 2, RetryDelay: time.Millisecond}
+	failed := importSymbols(context.Background(), cfg, []string{"AAPL"}, fn)
+	if len(failed) != 0 {
+		t.Fatalf("failures = %v, want success on the third attempt", failed)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestImportGivesUpAfterRetries(t *testing.T) {
+	attempts := 0
+	boom := errors.New("still broken")
+	fn := func(ctx context.Context, symbol string) error {
+		attempts++
+		return boom
+	}
+
+	cfg := ImportConfig{Retries: 1, RetryDelay: time.Millisecond}
+	failed := importSymbols(context.Background(), cfg, []string{"AAPL"}, fn)
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+	err, ok := failed["AAPL"]
+	if !ok || !errors.Is(err, boom) {
+		t.Fatalf("failed[AAPL] = %v, want the underlying error after 2 attempts", err)
+	}
+}
+
+func TestImportSymbolTimeoutBoundsEachAttempt(t *testing.T) {
+	fn := func(ctx context.Context, symbol string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	cfg := ImportConfig{SymbolTimeout: 10 * time.Millisecond}
+	failed := importSymbols(context.Background(), cfg, []string{"AAPL"}, fn)
+	if !errors.Is(failed["AAPL"], context.DeadlineExceeded) {
+		t.Fatalf("failed[AAPL] = %v, want a deadline error", failed["AAPL"])
+	}
+}
+
+func TestImportStopsRetryingWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	fn := func(ctx context.Context, symbol string) error {
+		attempts++
+		cancel()
+		return errors.New("transient")
+	}
+
+	cfg := ImportConfig{Retries: 5, RetryDelay: time.Millisecond}
+	failed := importSymbols(ctx, cfg, []string{"AAPL"}, fn)
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 once the context is dead", attempts)
+	}
+	if len(failed) != 1 {
+		t.Errorf("failures = %v, want the aborted symbol reported", failed)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/scheduler"
+)
+
+// runJobs implements `quotron jobs --config jobs.json`: run the
+// declared data-collection jobs on their schedules until interrupted.
+// With --run-once every job runs a single time and the command exits;
+// --since narrows that to jobs with no recorded success after the
+// given time, for catch-up after downtime.
+func runJobs(args []string) error {
+	fs := flag.NewFlagSet("jobs", flag.ExitOnError)
+	configPath := fs.String("config", "", "JSON file declaring the jobs to run")
+	apiURL := fs.String("api-url", "http://localhost:8080", "API service base URL the jobs pull through")
+	runOnce := fs.Bool("run-once", false, "run every job once and exit instead of scheduling")
+	preview := fs.Bool("preview", false, "print each job's fire times for the next 24h and exit without running anything")
+	sinceArg := fs.String("since", "", "with --run-once, only run jobs without a success after this time (RFC3339 or an age like 6h)")
+	statePath := fs.String("state", "", "file recording each job's last success time; required for --since")
+	holidaysArg := fs.String("holidays", "us", `holiday calendar for equity jobs: "us" for the built-in set, or a JSON file of YYYY-MM-DD dates`)
+	ignoreHolidays := fs.Bool("ignore-holidays", false, "run equity jobs on market holidays anyway (testing)")
+	healthWebhook := fs.String("health-webhook", "", "URL POSTed a JSON event when a job's health state changes (off when empty)")
+	healthRedis := fs.String("health-redis", "", "Redis address health events are streamed to (off when empty)")
+	healthStream := fs.String("health-stream", events.HealthStream, "stream health events are added to with --health-redis")
+	failAfter := fs.Int("fail-after", 0, "consecutive failures before a job counts as failed rather than degraded (0 = default)")
+	healthDebounce := fs.Duration("health-debounce", 0, "minimum gap between health alerts for one job (0 = default, negative = none)")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		return fmt.Errorf("--config is required")
+	}
+	cfgs, err := scheduler.LoadJobConfigs(*configPath)
+	if err != nil {
+		return err
+	}
+	if len(cfgs) == 0 {
+		return fmt.Errorf("%s declares no jobs", *configPath)
+	}
+	schedules, err := scheduler.BuildJobs(cfgs, scheduler.Deps{APIBaseURL: *apiURL})
+	if err != nil {
+		return err
+	}
+
+	if *preview {
+		previewSchedules(os.Stdout, schedules, time.Now())
+		return nil
+	}
+
+	sched := scheduler.NewScheduler()
+	notifier, closeNotifier, err := buildHealthNotifier(*healthWebhook, *healthRedis, *healthStream)
+	if err != nil {
+		return err
+	}
+	defer closeNotifier()
+	if notifier != nil {
+		sched.SetHealthNotifier(notifier, scheduler.HealthPolicy{
+			FailedAfter: *failAfter,
+			Debounce:    *healthDebounce,
+		})
+	}
+	if !*ignoreHolidays {
+		calendar, err := loadHolidayCalendar(*holidaysArg)
+		if err != nil {
+			return err
+		}
+		sched.SetHolidays(calendar)
+	}
+	for _, sch := range schedules {
+		sched.AddSchedule(sch)
+		if *runOnce {
+			continue
+		}
+		if sch.Jitter > 0 {
+			log.Printf("scheduled job %s every %s (jitter up to %s)", sch.Job.Name(), sch.Every, sch.Jitter)
+		} else {
+			log.Printf("scheduled job %s every %s", sch.Job.Name(), sch.Every)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *runOnce {
+		return runJobsOnce(ctx, sched, *sinceArg, *statePath)
+	}
+	if *sinceArg != "" {
+		return fmt.Errorf("--since only applies with --run-once")
+	}
+	sched.Start(ctx)
+
+	for _, st := range sched.History() {
+		log.Printf("job %s: %d runs, %d failures, %d skipped (overlap)", st.Name, st.Runs, st.Failures, st.Skips)
+	}
+	return nil
+}
+
+// runJobsOnce executes the run-once path: load last-success state,
+// filter by --since, run, and persist the new success times.
+func runJobsOnce(ctx context.Context, sched *scheduler.Scheduler, sinceArg, statePath string) error {
+	var since time.Time
+	if sinceArg != "" {
+		if statePath == "" {
+			return fmt.Errorf("--since needs --state to know when jobs last succeeded")
+		}
+		var err error
+		since, err = parseSince(sinceArg, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	lastSuccess, err := loadJobState(statePath)
+	if err != nil {
+		return err
+	}
+
+	succeeded := sched.RunOnce(ctx, since, lastSuccess)
+	if statePath != "" {
+		for name, at := range succeeded {
+			lastSuccess[name] = at
+		}
+		if err := saveJobState(statePath, lastSuccess); err != nil {
+			return err
+		}
+	}
+
+	var failures int
+	for _, st := range sched.History() {
+		failures += st.Failures
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d job(s) failed", failures)
+	}
+	return nil
+}
+
+// previewWindow is how far ahead --preview projects fire times.
+const previewWindow = 24 * time.Hour
+
+// previewMaxFires caps how many fire times --preview lists per job.
+const previewMaxFires = 10
+
+// previewSchedules prints what would run and when, without running
+// anything: each job's schedule and its projected fire times over the
+// preview window, starting from now (Start fires every job once
+// immediately).
+func previewSchedules(w io.Writer, schedules []scheduler.Schedule, now time.Time) {
+	for _, sch := range schedules {
+		extra := ""
+		if sch.Jitter > 0 {
+			extra = fmt.Sprintf(" (each run shifted up to %s by jitter)", sch.Jitter)
+		}
+		fmt.Fprintf(w, "%s: every %s%s\n", sch.Job.Name(), sch.Every, extra)
+		fires := sch.FireTimes(now, previewWindow, previewMaxFires)
+		for _, at := range fires {
+			fmt.Fprintf(w, "  %s\n", at.Format(time.RFC3339))
+		}
+		if total := int(previewWindow/sch.Every) + 1; total > len(fires) {
+			fmt.Fprintf(w, "  ... %d more in the next %s\n", total-len(fires), previewWindow)
+		}
+	}
+}
+
+// loadHolidayCalendar resolves the --holidays argument: the built-in US
+// set by default, or a JSON file listing YYYY-MM-DD dates.
+func loadHolidayCalendar(arg string) (*scheduler.HolidayCalendar, error) {
+	if arg == "" {
+		return nil, nil
+	}
+	if arg == "us" {
+		return scheduler.USMarketHolidays(), nil
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	var dates []string
+	if err := json.Unmarshal(data, &dates); err != nil {
+		return nil, fmt.Errorf("parsing holiday calendar %s: %w", arg, err)
+	}
+	return scheduler.NewHolidayCalendar(dates)
+}
+
+// parseSince accepts either an absolute RFC3339 time or an age ("6h"
+// means six hours before now).
+func parseSince(arg string, now time.Time) (time.Time, error) {
+	if age, err := time.ParseDuration(arg); err == nil {
+		return now.Add(-age), nil
+	}
+	at, err := time.Parse(time.RFC3339, arg)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q (want RFC3339 or an age like 6h)", arg)
+	}
+	return at, nil
+}
+
+// loadJobState reads the last-success times; a missing or unset file is
+// an empty state.
+func loadJobState(path string) (map[string]time.Time, error) {
+	state := map[string]time.Time{}
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing job state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveJobState writes the last-success times back to the state file.
+func saveJobState(path string, state map[string]time.Time) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
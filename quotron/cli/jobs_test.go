@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/scheduler"
+)
+
+// noopJob satisfies scheduler.Job without doing anything; preview must
+// never call Run.
+type noopJob struct{ name string }
+
+func (j noopJob) Name() string { return j.name }
+func (j noopJob) Run(ctx context.Context) error {
+	panic("preview must not run jobs")
+}
+
+func TestPreviewListsFireTimesWithoutRunning(t *testing.T) {
+	schedules := []scheduler.Schedule{
+		{Job: noopJob{name: "tech"}, Every: 8 * time.Hour, Jitter: 30 * time.Second},
+		{Job: noopJob{name: "coins"}, Every: time.Minute},
+	}
+	now := time.Date(2026, 8, 27, 9, 0, 0, 0, time.UTC)
+
+	var out strings.Builder
+	previewSchedules(&out, schedules, now)
+	got := out.String()
+
+	for _, want := range []string{
+		"tech: every 8h0m0s (each run shifted up to 30s by jitter)",
+		"  2026-08-27T09:00:00Z",
+		"  2026-08-28T09:00:00Z",
+		"coins: every 1m0s",
+		"more in the next 24h0m0s",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "\n") > 30 {
+		t.Errorf("preview output should cap fire times per job:\n%s", got)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 8, 27, 12, 0, 0, 0, time.UTC)
+
+	at, err := parseSince("6h", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-6 * time.Hour); !at.Equal(want) {
+		t.Errorf("parseSince(6h) = %v, want %v", at, want)
+	}
+
+	at, err = parseSince("2026-08-27T00:00:00Z", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 8, 27, 0, 0, 0, 0, time.UTC); !at.Equal(want) {
+		t.Errorf("parseSince(RFC3339) = %v, want %v", at, want)
+	}
+
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Error("parseSince should reject times it cannot parse")
+	}
+}
+
+func TestJobStateRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	at := time.Date(2026, 8, 27, 9, 30, 0, 0, time.UTC)
+	if err := saveJobState(path, map[string]time.Time{"tech": at}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := loadJobState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state["tech"].Equal(at) {
+		t.Errorf("state[tech] = %v, want %v", state["tech"], at)
+	}
+}
+
+func TestMissingJobStateIsEmpty(t *testing.T) {
+	state, err := loadJobState(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state) != 0 {
+		t.Errorf("state = %v, want empty for a missing file", state)
+	}
+}
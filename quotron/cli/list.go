@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/db"
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// runList implements `quotron list [--symbols ...] [--exchanges ...]`:
+// print stored quotes matching the filters, newest first. The SQL goes
+// through the shared parameterized builder, so the filter values are
+// never spliced into the statement.
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	symbolsArg := fs.String("symbols", "", "comma-separated symbols to match (empty = all)")
+	exchangesArg := fs.String("exchanges", "", "comma-separated exchanges to match (empty = all)")
+	sourcesArg := fs.String("sources", "", "comma-separated sources to match (empty = all)")
+	since := fs.Duration("since", 0, "only quotes newer than this lookback, e.g. 24h (0 = no bound)")
+	limit := fs.Int("limit", db.DefaultListLimit, "maximum rows to print")
+	offset := fs.Int("offset", 0, "rows to skip, for paging")
+	dbURL := fs.String("db-url", defaultDBURL(), "Postgres connection string")
+	fs.Parse(args)
+
+	if *dbURL == "" {
+		return fmt.Errorf("--db-url or DATABASE_URL is required")
+	}
+
+	filter := db.QuoteFilter{
+		Symbols:   splitArg(*symbolsArg),
+		Exchanges: splitArg(*exchangesArg),
+		Sources:   splitArg(*sourcesArg),
+		Limit:     *limit,
+		Offset:    *offset,
+	}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	conn, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.Ping(); err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+
+	quotes, err := listQuotes(context.Background(), conn, filter)
+	if err != nil {
+		return err
+	}
+	if len(quotes) == 0 {
+		fmt.Println("no quotes match")
+		return nil
+	}
+	for _, q := range quotes {
+		fmt.Printf("%s  %-6s %10.2f %+8.2f%%  %-8s %s\n",
+			q.Timestamp.Format(time.RFC3339), q.Symbol, q.Price, q.ChangePercent, q.Exchange, q.Source)
+	}
+	return nil
+}
+
+// listQuotes runs the built filter query and scans the rows.
+func listQuotes(ctx context.Context, conn *sql.DB, filter db.QuoteFilter) ([]*models.StockQuote, error) {
+	ctx, cancel := queryCtx(ctx)
+	defer cancel()
+
+	query, args := db.BuildQuoteQuery(filter)
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*models.StockQuote
+	for rows.Next() {
+		var q models.StockQuote
+		var volume sql.NullInt64
+		var high, low sql.NullFloat64
+		if err := rows.Scan(&q.Symbol, &q.Price, &q.Change, &q.ChangePercent,
+			&volume, &high, &low, &q.Timestamp, &q.Exchange, &q.Source); err != nil {
+			return nil, err
+		}
+		if volume.Valid {
+			q.Volume = &volume.Int64
+		}
+		if high.Valid {
+			q.High24h = &high.Float64
+		}
+		if low.Valid {
+			q.Low24h = &low.Float64
+		}
+		quotes = append(quotes, &q)
+	}
+	return quotes, rows.Err()
+}
+
+// splitArg parses a comma-separated flag value, trimming blanks and
+// uppercasing nothing: exchanges and sources are case-sensitive.
+func splitArg(arg string) []string {
+	if arg == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(arg, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
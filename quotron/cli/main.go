@@ -0,0 +1,48 @@
+// quotron is the operations CLI for the quotron services: data
+// backfills, stream tooling, and other one-shot tasks.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// commands maps each subcommand name to its implementation. Each
+// subcommand owns its own flag set.
+var commands = map[string]func(args []string) error{
+	"backfill":    runBackfill,
+	"jobs":        runJobs,
+	"list":        runList,
+	"migrate":     runMigrate,
+	"publish":     runPublish,
+	"rollback":    runRollback,
+	"stream-trim": runStreamTrim,
+	"tail":        runTail,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: quotron <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for name := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", name)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "quotron %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
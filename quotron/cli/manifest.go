@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// importManifest records which symbols an import has completed, so a
+// rerun resumes where the last one stopped instead of restarting.
+type importManifest struct {
+	path string
+
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+// manifestFile is the on-disk shape: a sorted list of completed symbols.
+type manifestFile struct {
+	Completed []string `json:"completed"`
+}
+
+// loadManifest reads the manifest at path; a missing file is an empty
+// manifest, so the first run needs no setup.
+func loadManifest(path string) (*importManifest, error) {
+	m := &importManifest{path: path, done: map[string]bool{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file manifestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	for _, symbol := range file.Completed {
+		m.done[symbol] = true
+	}
+	return m, nil
+}
+
+// Done reports whether the symbol completed in an earlier run.
+func (m *importManifest) Done(symbol string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.done[symbol]
+}
+
+// MarkDone records a completed symbol and persists the manifest right
+// away, so a crash loses at most the symbol in flight.
+func (m *importManifest) MarkDone(symbol string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.done[symbol] = true
+
+	file := manifestFile{Completed: make([]string, 0, len(m.done))}
+	for s := range m.done {
+		file.Completed = append(file.Completed, s)
+	}
+	sort.Strings(file.Completed)
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, append(data, '\n'), 0o644)
+}
+
+// resumeImport splits symbols into the ones an earlier run already
+// completed and the remainder, and wraps fn so each new success is
+// recorded in the manifest.
+func resumeImport(m *importManifest, symbols []string, fn importFunc) (skipped, remaining []string, wrapped importFunc) {
+	for _, symbol := range symbols {
+		if m.Done(symbol) {
+			skipped = append(skipped, symbol)
+		} else {
+			remaining = append(remaining, symbol)
+		}
+	}
+	wrapped = func(ctx context.Context, symbol string) error {
+		if err := fn(ctx, symbol); err != nil {
+			return err
+		}
+		if err := m.MarkDone(symbol); err != nil {
+			return fmt.Errorf("recording %s in manifest: %w", symbol, err)
+		}
+		return nil
+	}
+	return skipped, remaining, wrapped
+}
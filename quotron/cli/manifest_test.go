@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMissingManifestIsEmpty(t *testing.T) {
+	m, err := loadManifest(filepath.Join(t.TempDir(), "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Done("AAPL") {
+		t.Error("fresh manifest should have no completed symbols")
+	}
+}
+
+func TestManifestPersistsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MarkDone("AAPL"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.Done("AAPL") {
+		t.Error("reloaded manifest lost the completed symbol")
+	}
+	if reloaded.Done("MSFT") {
+		t.Error("reloaded manifest invented a completed symbol")
+	}
+}
+
+func TestImportResumesFromPartialManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MarkDone("AAPL"); err != nil {
+		t.Fatal(err)
+	}
+
+	// First resumed run: AAPL is skipped, MSFT succeeds, GOOG fails.
+	var attempted []string
+	fn := func(ctx context.Context, symbol string) error {
+		attempted = append(attempted, symbol)
+		if symbol == "GOOG" {
+			return errors.New("upstream exploded")
+		}
+		return nil
+	}
+	skipped, remaining, wrapped := resumeImport(m, []string{"AAPL", "MSFT", "GOOG"}, fn)
+	if len(skipped) != 1 || skipped[0] != "AAPL" {
+		t.Fatalf("skipped = %v, want [AAPL]", skipped)
+	}
+	failed := importSymbols(context.Background(), ImportConfig{}, remaining, wrapped)
+	if len(attempted) != 2 || attempted[0] != "MSFT" || attempted[1] != "GOOG" {
+		t.Fatalf("attempted = %v, want [MSFT GOOG]", attempted)
+	}
+	if len(failed) != 1 {
+		t.Fatalf("failures = %v, want just GOOG", failed)
+	}
+
+	// Second run resumes with only the failure left.
+	reloaded, err := loadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, remaining, _ = resumeImport(reloaded, []string{"AAPL", "MSFT", "GOOG"}, fn)
+	if len(remaining) != 1 || remaining[0] != "GOOG" {
+		t.Errorf("remaining = %v, want only the failed symbol", remaining)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/we-be/tiny-ria/quotron/ingest"
+)
+
+// runRollback implements `quotron rollback [-n N]`: undo the last N
+// applied migrations by running their _down.sql files in reverse order.
+// Destructive, so it asks for confirmation unless --force is given.
+func runRollback(args []string) error {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	n := fs.Int("n", 1, "how many migrations to roll back")
+	force := fs.Bool("force", false, "skip the confirmation prompt")
+	dbURL := fs.String("db-url", defaultDBURL(), "Postgres connection string")
+	migrationsDir := fs.String("migrations-dir", "", "read migrations from this directory instead of the embedded files")
+	fs.Parse(args)
+
+	if *dbURL == "" {
+		return fmt.Errorf("--db-url or DATABASE_URL is required")
+	}
+	if *migrationsDir != "" {
+		ingest.SetMigrationsDir(*migrationsDir)
+	}
+	if !*force && !confirm(fmt.Sprintf("Roll back the last %d migration(s)? This drops schema (and data). [y/N]: ", *n)) {
+		return fmt.Errorf("aborted")
+	}
+
+	db, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+
+	undone, err := ingest.RollbackMigrations(context.Background(), db, *n)
+	for _, name := range undone {
+		fmt.Printf("rolled back %s\n", name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(undone) == 0 {
+		fmt.Println("nothing to roll back")
+	}
+	return nil
+}
+
+// confirm prompts on stdout and reads a y/yes answer from stdin.
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	var answer string
+	fmt.Scanln(&answer)
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// runMigrate implements `quotron migrate`: apply every migration not yet
+// recorded in schema_migrations, in order, and report what ran.
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dbURL := fs.String("db-url", defaultDBURL(), "Postgres connection string")
+	migrationsDir := fs.String("migrations-dir", "", "read migrations from this directory instead of the embedded files")
+	fs.Parse(args)
+
+	if *dbURL == "" {
+		return fmt.Errorf("--db-url or DATABASE_URL is required")
+	}
+	if *migrationsDir != "" {
+		ingest.SetMigrationsDir(*migrationsDir)
+	}
+
+	db, err := sql.Open("postgres", *dbURL)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+
+	applied, err := ingest.RunMigrations(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("database is up to date")
+		return nil
+	}
+	for _, name := range applied {
+		fmt.Printf("applied %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/redis"
+)
+
+// runPublish implements `quotron publish`: seed a stream (or pub/sub
+// channel) with synthetic quotes for testing consumers, replacing the
+// throwaway one-off publisher scripts with one maintained tool.
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	redisAddr := fs.String("redis", "localhost:6379", "Redis address")
+	stream := fs.String("stream", events.AlertStream, "stream to append entries to")
+	channel := fs.String("channel", "", "publish to this pub/sub channel (as JSON) instead of a stream")
+	symbol := fs.String("symbol", "AAPL", "symbol for the synthetic quotes")
+	price := fs.Float64("price", 150, "base price")
+	percent := fs.Float64("percent-change", 0, "percent change on each quote")
+	count := fs.Int("count", 1, "entries to publish")
+	interval := fs.Duration("interval", 0, "pause between entries")
+	random := fs.Bool("random", false, "randomize each quote around the base price (±5%, move ±3%)")
+	maxLen := fs.Int("maxlen", 0, "trim the stream to about this many entries while publishing (0 = no trim)")
+	exact := fs.Bool("exact", false, "trim to exactly --maxlen instead of approximately (slower)")
+	fs.Parse(args)
+
+	if *count < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+	target := *stream
+	if *channel != "" {
+		target = *channel
+	}
+
+	conn, err := redis.Dial(*redisAddr)
+	if err != nil {
+		return fmt.Errorf("connecting to Redis: %w", err)
+	}
+	defer conn.Close()
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < *count; i++ {
+		if i > 0 && *interval > 0 {
+			time.Sleep(*interval)
+		}
+		quote := makeQuote(*symbol, *price, *percent, *random, rng)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := conn.Do(publishCommand(*stream, *channel, *maxLen, *exact, quote)...); err != nil {
+			return fmt.Errorf("publishing entry %d: %w", i+1, err)
+		}
+		fmt.Printf("published %s @ %.2f (%+.2f%%) to %s\n",
+			quote.Symbol, quote.Price, quote.PercentChange, target)
+	}
+	return nil
+}
+
+// syntheticQuote is one seeded entry. On a stream it travels as the
+// flat field/value envelope the agent consumes; on a pub/sub channel it
+// goes out as JSON.
+type syntheticQuote struct {
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	PercentChange float64   `json:"percent_change"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// makeQuote builds one synthetic quote from the base values; with
+// random, the price wobbles within ±5% and the move is drawn from ±3%.
+func makeQuote(symbol string, price, percent float64, random bool, rng *rand.Rand) syntheticQuote {
+	quote := syntheticQuote{
+		Symbol:        symbol,
+		Price:         price,
+		PercentChange: percent,
+		Timestamp:     time.Now().UTC(),
+	}
+	if random {
+		quote.Price *= 1 + (rng.Float64()-0.5)/10
+		quote.PercentChange = (rng.Float64() - 0.5) * 6
+	}
+	return quote
+}
+
+// fields flattens the quote into the stream envelope's field/value list.
+func (q syntheticQuote) fields() []string {
+	return []string{
+		"symbol", q.Symbol,
+		"price", strconv.FormatFloat(q.Price, 'f', -1, 64),
+		"percent_change", strconv.FormatFloat(q.PercentChange, 'f', -1, 64),
+		"timestamp", q.Timestamp.Format(time.RFC3339),
+	}
+}
+
+// publishCommand assembles the Redis command for one entry: XADD onto
+// the stream (trimming inline when a max length is set), or PUBLISH
+// with a JSON payload when a channel was chosen.
+func publishCommand(stream, channel string, maxLen int, exact bool, quote syntheticQuote) []string {
+	if channel != "" {
+		payload, _ := json.Marshal(quote)
+		return []string{"PUBLISH", channel, string(payload)}
+	}
+	cmd := append([]string{"XADD", stream}, trimArgs(maxLen, exact)...)
+	return append(append(cmd, "*"), quote.fields()...)
+}
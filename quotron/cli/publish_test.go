@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuoteFieldsMatchTheStreamEnvelope(t *testing.T) {
+	quote := makeQuote("AAPL", 150, -2.5, false, nil)
+	fields := quote.fields()
+	if len(fields)%2 != 0 {
+		t.Fatalf("fields = %v, want field/value pairs", fields)
+	}
+	got := map[string]string{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		got[fields[i]] = fields[i+1]
+	}
+	if got["symbol"] != "AAPL" || got["price"] != "150" || got["percent_change"] != "-2.5" {
+		t.Errorf("fields = %v, want the base values carried through", got)
+	}
+	if _, err := time.Parse(time.RFC3339, got["timestamp"]); err != nil {
+		t.Errorf("timestamp %q: %v", got["timestamp"], err)
+	}
+}
+
+func TestRandomQuotesStayNearTheBase(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		quote := makeQuote("AAPL", 100, 0, true, rng)
+		if quote.Price < 95 || quote.Price > 105 {
+			t.Fatalf("price %v escaped the ±5%% band", quote.Price)
+		}
+		if quote.PercentChange < -3 || quote.PercentChange > 3 {
+			t.Fatalf("percent change %v escaped the ±3%% band", quote.PercentChange)
+		}
+	}
+
+	first := makeQuote("AAPL", 100, 0, true, rng)
+	second := makeQuote("AAPL", 100, 0, true, rng)
+	if first.Price == second.Price && first.PercentChange == second.PercentChange {
+		t.Error("consecutive random quotes should differ")
+	}
+}
+
+func TestPublishCommandTargetsStreamOrChannel(t *testing.T) {
+	quote := makeQuote("BTC-USD", 65000, 1.1, false, nil)
+
+	cmd := publishCommand("quotron:alerts", "", 0, false, quote)
+	if cmd[0] != "XADD" || cmd[1] != "quotron:alerts" || cmd[2] != "*" {
+		t.Errorf("stream command = %v, want an XADD with auto ID", cmd)
+	}
+	if !strings.Contains(strings.Join(cmd, " "), "BTC-USD") {
+		t.Errorf("stream command = %v, want the quote fields appended", cmd)
+	}
+
+	cmd = publishCommand("quotron:alerts", "quotron:live", 0, false, quote)
+	if cmd[0] != "PUBLISH" || cmd[1] != "quotron:live" || len(cmd) != 3 {
+		t.Fatalf("channel command = %v, want PUBLISH with one payload", cmd)
+	}
+	var payload syntheticQuote
+	if err := json.Unmarshal([]byte(cmd[2]), &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.Symbol != "BTC-USD" || payload.Price != 65000 {
+		t.Errorf("payload = %+v, want the quote serialized", payload)
+	}
+}
+
+func TestQuoteFieldsRoundTripPrecision(t *testing.T) {
+	quote := makeQuote("MSFT", 410.125, 0.333, false, nil)
+	fields := quote.fields()
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] != "price" {
+			continue
+		}
+		price, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil || price != quote.Price {
+			t.Errorf("price field %q round-tripped to %v (err %v)", fields[i+1], price, err)
+		}
+	}
+}
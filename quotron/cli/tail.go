@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/redis"
+)
+
+// tailBlockMillis is how long one XREAD call blocks while following.
+const tailBlockMillis = 5000
+
+// runTail implements `quotron tail`: read a stream and pretty-print its
+// entries, the consumer-side twin of `quotron publish`. By default it
+// prints the last --count entries; --follow keeps reading new ones
+// until interrupted, and --raw dumps the undecoded field/value pairs.
+func runTail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	redisAddr := fs.String("redis", "localhost:6379", "Redis address")
+	stream := fs.String("stream", events.AlertStream, "stream to read")
+	from := fs.String("from", "latest", "where to start: latest, start, or a stream entry ID")
+	count := fs.Int("count", 10, "entries to print when not following")
+	follow := fs.Bool("follow", false, "keep printing new entries until interrupted")
+	raw := fs.Bool("raw", false, "dump unparsed field/value pairs")
+	fs.Parse(args)
+
+	if *count < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+
+	conn, err := redis.Dial(*redisAddr)
+	if err != nil {
+		return fmt.Errorf("connecting to Redis: %w", err)
+	}
+	defer conn.Close()
+
+	if *follow {
+		return tailFollow(conn, *stream, *from, *raw)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reply, err := conn.Do(rangeCommand(*stream, *from, *count)...)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", *stream, err)
+	}
+	entries := entriesFromRange(reply)
+	if *from == "latest" {
+		// XREVRANGE walks newest-first; print in stream order.
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Printf("no entries on %s\n", *stream)
+		return nil
+	}
+	for _, entry := range entries {
+		fmt.Println(formatEntry(entry, *raw))
+	}
+	return nil
+}
+
+// tailFollow blocks on XREAD and prints entries as they arrive, until
+// the process is interrupted.
+func tailFollow(conn *redis.Conn, stream, from string, raw bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cursor := followCursor(from)
+	for ctx.Err() == nil {
+		// Bound the socket read a little past the server-side block time.
+		conn.SetReadDeadline(time.Now().Add(tailBlockMillis*time.Millisecond + 2*time.Second))
+		reply, err := conn.Do("XREAD", "BLOCK", strconv.Itoa(tailBlockMillis),
+			"COUNT", "50", "STREAMS", stream, cursor)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("reading %s: %w", stream, err)
+		}
+		for _, entry := range entriesFromRead(reply) {
+			fmt.Println(formatEntry(entry, raw))
+			cursor = entry.id
+		}
+	}
+	return nil
+}
+
+// rangeCommand builds the one-shot read: the newest entries for
+// "latest", the oldest for "start", and everything from an explicit ID
+// onward otherwise.
+func rangeCommand(stream, from string, count int) []string {
+	switch from {
+	case "latest":
+		return []string{"XREVRANGE", stream, "+", "-", "COUNT", strconv.Itoa(count)}
+	case "start":
+		return []string{"XRANGE", stream, "-", "+", "COUNT", strconv.Itoa(count)}
+	}
+	return []string{"XRANGE", stream, from, "+", "COUNT", strconv.Itoa(count)}
+}
+
+// followCursor maps --from onto the XREAD start position.
+func followCursor(from string) string {
+	switch from {
+	case "latest":
+		return "$"
+	case "start":
+		return "0"
+	}
+	return from
+}
+
+// streamEntry is one decoded stream entry: its ID and the flat
+// field/value list.
+type streamEntry struct {
+	id     string
+	fields []string
+}
+
+// entriesFromRange unpacks an XRANGE/XREVRANGE reply
+// ([[id, [field, value, ...]], ...]).
+func entriesFromRange(reply interface{}) []streamEntry {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil
+	}
+	var entries []streamEntry
+	for _, item := range items {
+		pair, ok := item.([]interface{})
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		entry := streamEntry{}
+		entry.id, _ = pair[0].(string)
+		values, _ := pair[1].([]interface{})
+		for _, v := range values {
+			s, _ := v.(string)
+			entry.fields = append(entry.fields, s)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// entriesFromRead unpacks an XREAD reply, which nests the range shape
+// one level deeper per stream ([[stream, [entries]], ...]).
+func entriesFromRead(reply interface{}) []streamEntry {
+	streams, ok := reply.([]interface{})
+	if !ok {
+		return nil
+	}
+	var entries []streamEntry
+	for _, s := range streams {
+		pair, ok := s.([]interface{})
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		entries = append(entries, entriesFromRange(pair[1])...)
+	}
+	return entries
+}
+
+// formatEntry renders one entry. Entries in the shared event schema
+// come out as one readable quote line; anything else (or --raw) falls
+// back to the bare field/value pairs.
+func formatEntry(entry streamEntry, raw bool) string {
+	values := map[string]string{}
+	for i := 0; i+1 < len(entry.fields); i += 2 {
+		values[entry.fields[i]] = entry.fields[i+1]
+	}
+	if raw || values["symbol"] == "" {
+		return fmt.Sprintf("%s  %s", entry.id, strings.Join(entry.fields, " "))
+	}
+
+	line := fmt.Sprintf("%s  %s @ %s", entry.id, values["symbol"], values["price"])
+	if change := values["percent_change"]; change != "" {
+		if !strings.HasPrefix(change, "-") {
+			change = "+" + change
+		}
+		line += fmt.Sprintf(" (%s%%)", change)
+	}
+	if ts := values["timestamp"]; ts != "" {
+		line += "  " + ts
+	}
+	return line
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// rangeReply builds the XRANGE reply shape for the given entries.
+func rangeReply(entries ...streamEntry) []interface{} {
+	var reply []interface{}
+	for _, entry := range entries {
+		fields := make([]interface{}, len(entry.fields))
+		for i, f := range entry.fields {
+			fields[i] = f
+		}
+		reply = append(reply, []interface{}{entry.id, fields})
+	}
+	return reply
+}
+
+func TestRangeCommandPerStartPosition(t *testing.T) {
+	cases := []struct {
+		from, want string
+	}{
+		{"latest", "XREVRANGE s + - COUNT 5"},
+		{"start", "XRANGE s - + COUNT 5"},
+		{"1699-0", "XRANGE s 1699-0 + COUNT 5"},
+	}
+	for _, c := range cases {
+		if got := strings.Join(rangeCommand("s", c.from, 5), " "); got != c.want {
+			t.Errorf("rangeCommand(%q) = %q, want %q", c.from, got, c.want)
+		}
+	}
+}
+
+func TestFollowCursorPerStartPosition(t *testing.T) {
+	for from, want := range map[string]string{"latest": "$", "start": "0", "1699-0": "1699-0"} {
+		if got := followCursor(from); got != want {
+			t.Errorf("followCursor(%q) = %q, want %q", from, got, want)
+		}
+	}
+}
+
+func TestEntriesFromRangeAndRead(t *testing.T) {
+	entry := streamEntry{id: "1-1", fields: []string{"symbol", "AAPL", "price", "150"}}
+
+	got := entriesFromRange(rangeReply(entry))
+	if len(got) != 1 || got[0].id != "1-1" || got[0].fields[1] != "AAPL" {
+		t.Errorf("entriesFromRange = %+v, want the decoded entry", got)
+	}
+
+	read := []interface{}{[]interface{}{"quotron:alerts", rangeReply(entry)}}
+	got = entriesFromRead(read)
+	if len(got) != 1 || got[0].id != "1-1" {
+		t.Errorf("entriesFromRead = %+v, want the entry unwrapped from the stream level", got)
+	}
+
+	if entries := entriesFromRead(nil); entries != nil {
+		t.Errorf("a nil (timed out) reply should decode to no entries, got %+v", entries)
+	}
+}
+
+func TestFormatEntryShapes(t *testing.T) {
+	entry := streamEntry{id: "1-1", fields: []string{
+		"symbol", "AAPL", "price", "150", "percent_change", "2.5",
+		"timestamp", "2025-06-02T15:30:00Z",
+	}}
+
+	pretty := formatEntry(entry, false)
+	for _, want := range []string{"1-1", "AAPL @ 150", "(+2.5%)", "2025-06-02T15:30:00Z"} {
+		if !strings.Contains(pretty, want) {
+			t.Errorf("formatEntry = %q, want it to contain %q", pretty, want)
+		}
+	}
+
+	if raw := formatEntry(entry, true); !strings.Contains(raw, "symbol AAPL price 150") {
+		t.Errorf("raw format = %q, want the bare pairs", raw)
+	}
+
+	// Entries outside the event schema degrade to the raw dump.
+	odd := streamEntry{id: "2-1", fields: []string{"rate", "1.07"}}
+	if got := formatEntry(odd, false); !strings.Contains(got, "rate 1.07") {
+		t.Errorf("formatEntry = %q, want the raw fallback for unknown schemas", got)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/events"
+	"github.com/we-be/tiny-ria/quotron/redis"
+)
+
+// runStreamTrim implements `quotron stream-trim`: cap a stream at a
+// maximum length so busy deployments can reclaim memory without
+// restarting publishers. Trimming is approximate by default — Redis
+// drops whole internal nodes, which is far cheaper than entry-by-entry
+// — with --exact for when the precise count matters.
+func runStreamTrim(args []string) error {
+	fs := flag.NewFlagSet("stream-trim", flag.ExitOnError)
+	redisAddr := fs.String("redis", "localhost:6379", "Redis address")
+	stream := fs.String("stream", events.AlertStream, "stream to trim")
+	maxLen := fs.Int("maxlen", 0, "entries to keep")
+	exact := fs.Bool("exact", false, "trim to exactly --maxlen instead of approximately (slower)")
+	fs.Parse(args)
+
+	if *maxLen < 1 {
+		return fmt.Errorf("--maxlen is required")
+	}
+
+	conn, err := redis.Dial(*redisAddr)
+	if err != nil {
+		return fmt.Errorf("connecting to Redis: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	reply, err := conn.Do(trimCommand(*stream, *maxLen, *exact)...)
+	if err != nil {
+		return fmt.Errorf("trimming %s: %w", *stream, err)
+	}
+	removed, _ := reply.(int64)
+	fmt.Printf("trimmed %d entries from %s\n", removed, *stream)
+	return nil
+}
+
+// trimCommand builds the XTRIM for a stream and length policy.
+func trimCommand(stream string, maxLen int, exact bool) []string {
+	return append([]string{"XTRIM", stream}, trimArgs(maxLen, exact)...)
+}
+
+// trimArgs renders the MAXLEN clause shared by XADD and XTRIM:
+// approximate ("~") unless exact trimming was asked for.
+func trimArgs(maxLen int, exact bool) []string {
+	if maxLen <= 0 {
+		return nil
+	}
+	if exact {
+		return []string{"MAXLEN", strconv.Itoa(maxLen)}
+	}
+	return []string{"MAXLEN", "~", strconv.Itoa(maxLen)}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimArgsPolicy(t *testing.T) {
+	cases := []struct {
+		maxLen int
+		exact  bool
+		want   string
+	}{
+		{0, false, ""},
+		{1000, false, "MAXLEN ~ 1000"},
+		{1000, true, "MAXLEN 1000"},
+	}
+	for _, c := range cases {
+		if got := strings.Join(trimArgs(c.maxLen, c.exact), " "); got != c.want {
+			t.Errorf("trimArgs(%d, %v) = %q, want %q", c.maxLen, c.exact, got, c.want)
+		}
+	}
+}
+
+func TestTrimCommand(t *testing.T) {
+	want := "XTRIM quotron:alerts MAXLEN ~ 5000"
+	if got := strings.Join(trimCommand("quotron:alerts", 5000, false), " "); got != want {
+		t.Errorf("trimCommand = %q, want %q", got, want)
+	}
+}
+
+func TestPublishCommandTrimsInline(t *testing.T) {
+	quote := makeQuote("AAPL", 150, 0, false, nil)
+
+	got := strings.Join(publishCommand("quotron:alerts", "", 1000, false, quote), " ")
+	if !strings.HasPrefix(got, "XADD quotron:alerts MAXLEN ~ 1000 *") {
+		t.Errorf("command = %q, want the approximate trim before the ID", got)
+	}
+
+	got = strings.Join(publishCommand("quotron:alerts", "", 1000, true, quote), " ")
+	if !strings.HasPrefix(got, "XADD quotron:alerts MAXLEN 1000 *") {
+		t.Errorf("command = %q, want the exact trim before the ID", got)
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is where a source's circuit breaker currently sits.
+type BreakerState string
+
+const (
+	// BreakerClosed is normal operation: calls flow through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fast-fails every call until the cooldown elapses, so a
+	// rate-limited source stops being hammered and failover is immediate.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe through to test recovery.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerConfig tunes a source's circuit breaker.
+type BreakerConfig struct {
+	// FailureThreshold is how many consecutive failures within Window
+	// open the circuit.
+	FailureThreshold int
+	// Window bounds how long a failure streak stays relevant; a streak
+	// that goes quiet longer than this starts counting from scratch.
+	Window time.Duration
+	// Cooldown is how long an open circuit fast-fails before letting a
+	// probe through.
+	Cooldown time.Duration
+}
+
+// DefaultBreakerConfig matches the proxy's observed failure modes:
+// five straight errors inside a minute means down, not unlucky.
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	Window:           time.Minute,
+	Cooldown:         30 * time.Second,
+}
+
+// breaker is the per-source circuit breaker state machine.
+type breaker struct {
+	cfg BreakerConfig
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	firstFail time.Time
+	openedAt  time.Time
+	probing   bool
+	now       func() time.Time // injectable for tests
+}
+
+func newBreaker(cfg BreakerConfig) *breaker {
+	return &breaker{cfg: cfg, state: BreakerClosed, now: time.Now}
+}
+
+// allow reports whether a call may proceed, moving an open circuit to
+// half-open once the cooldown has elapsed. In half-open only one probe
+// is in flight at a time; the rest keep fast-failing.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.Cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record feeds one call's outcome back into the state machine.
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	if b.state == BreakerHalfOpen {
+		// The probe failed: back to fast-failing for another cooldown.
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		return
+	}
+
+	now := b.now()
+	if b.failures == 0 || now.Sub(b.firstFail) > b.cfg.Window {
+		b.failures = 0
+		b.firstFail = now
+	}
+	b.failures++
+	if b.failures >= b.cfg.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+// snapshot returns the state for health reporting.
+func (b *breaker) snapshot() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
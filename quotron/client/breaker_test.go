@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func testBreaker() (*breaker, *time.Time) {
+	b := newBreaker(BreakerConfig{
+		FailureThreshold: 3,
+		Window:           time.Minute,
+		Cooldown:         30 * time.Second,
+	})
+	now := time.Unix(1700000000, 0)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b, _ := testBreaker()
+
+	b.record(errGone)
+	b.record(errGone)
+	if b.snapshot() != BreakerClosed || !b.allow() {
+		t.Fatal("two failures should leave the breaker closed")
+	}
+
+	b.record(errGone)
+	if b.snapshot() != BreakerOpen {
+		t.Fatalf("state = %s after 3 failures, want open", b.snapshot())
+	}
+	if b.allow() {
+		t.Error("open breaker should fast-fail before the cooldown")
+	}
+}
+
+func TestBreakerRecoversThroughHalfOpen(t *testing.T) {
+	b, now := testBreaker()
+	for i := 0; i < 3; i++ {
+		b.record(errGone)
+	}
+
+	// Cooldown over: one probe gets through, a second is held back.
+	*now = now.Add(31 * time.Second)
+	if !b.allow() {
+		t.Fatal("want a probe allowed after the cooldown")
+	}
+	if b.snapshot() != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open", b.snapshot())
+	}
+	if b.allow() {
+		t.Error("only one half-open probe should be in flight")
+	}
+
+	// Failed probe reopens for another full cooldown.
+	b.record(errGone)
+	if b.snapshot() != BreakerOpen || b.allow() {
+		t.Fatal("failed probe should reopen the breaker")
+	}
+
+	// Successful probe closes it again.
+	*now = now.Add(31 * time.Second)
+	if !b.allow() {
+		t.Fatal("want a probe allowed after the second cooldown")
+	}
+	b.record(nil)
+	if b.snapshot() != BreakerClosed {
+		t.Fatalf("state = %s after successful probe, want closed", b.snapshot())
+	}
+}
+
+func TestBreakerWindowResetsStaleStreaks(t *testing.T) {
+	b, now := testBreaker()
+	b.record(errGone)
+	b.record(errGone)
+
+	// The streak goes quiet longer than the window; the next failure
+	// starts a new streak instead of tripping the breaker.
+	*now = now.Add(2 * time.Minute)
+	b.record(errGone)
+	if b.snapshot() != BreakerClosed {
+		t.Fatalf("state = %s, want closed: the old streak expired", b.snapshot())
+	}
+}
+
+func TestOpenBreakerSkipsTheSource(t *testing.T) {
+	primary := &countingFailClient{err: errGone}
+	m := NewClientManager(primary, staticClient{price: 7})
+	m.SetBreakerConfig(BreakerConfig{
+		FailureThreshold: 2,
+		Window:           time.Minute,
+		Cooldown:         time.Hour,
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := m.GetStockQuote(context.Background(), "AAPL"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt64(&primary.calls); got != 2 {
+		t.Errorf("primary calls = %d, want 2: the open breaker should fast-fail the third", got)
+	}
+	if h := m.GetClientHealth()[0]; h.Breaker != BreakerOpen {
+		t.Errorf("primary breaker = %s, want open", h.Breaker)
+	}
+}
+
+func TestAggregatedErrorNamesOpenCircuits(t *testing.T) {
+	m := NewClientManager(&failingClient{err: errGone})
+	m.SetBreakerConfig(BreakerConfig{
+		FailureThreshold: 1,
+		Window:           time.Minute,
+		Cooldown:         time.Hour,
+	})
+
+	m.GetStockQuote(context.Background(), "AAPL")
+	_, err := m.GetStockQuote(context.Background(), "AAPL")
+	if err == nil || !strings.Contains(err.Error(), "circuit open") {
+		t.Errorf("error = %v, want it to name the open circuit", err)
+	}
+}
+
+// countingFailClient errors on every call and counts how many reach it.
+type countingFailClient struct {
+	staticClient
+	calls int64
+	err   error
+}
+
+func (c *countingFailClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	atomic.AddInt64(&c.calls, 1)
+	return nil, c.err
+}
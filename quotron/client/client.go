@@ -0,0 +1,125 @@
+// Package client provides the HTTP client for the upstream quote proxy,
+// shared by the API service and the CLI tools.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// ErrNotFound marks an upstream 404: the source answered, it just has
+// no data for the requested symbol. Callers check it with errors.Is to
+// distinguish a bad symbol from a broken source.
+var ErrNotFound = errors.New("not found upstream")
+
+// DataClient fetches quotes from an upstream source. The API service
+// uses it when storage has no fresh data; --mock swaps in a synthetic
+// implementation.
+type DataClient interface {
+	GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error)
+	GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error)
+	GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error)
+}
+
+// ProxyClient talks to the local quote proxy (the Yahoo Finance scraper).
+type ProxyClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewProxyClient builds a client for the proxy at baseURL.
+func NewProxyClient(baseURL string) *ProxyClient {
+	return &ProxyClient{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Ping reports whether the proxy is reachable at all. Any HTTP answer
+// counts — readiness cares about the process being up, not about a
+// particular endpoint existing.
+func (c *ProxyClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("upstream proxy unreachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *ProxyClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", path, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upstream returned %s for %s", resp.Status, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GetStockQuote fetches the current quote for a symbol from the proxy.
+func (c *ProxyClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	var quote models.StockQuote
+	if err := c.get(ctx, "/quote/"+url.PathEscape(symbol), &quote); err != nil {
+		return nil, err
+	}
+	if quote.Timestamp.IsZero() {
+		quote.Timestamp = time.Now().UTC()
+	}
+	return &quote, nil
+}
+
+// GetIntradaySeries fetches OHLC buckets for a symbol from the proxy.
+func (c *ProxyClient) GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error) {
+	path := fmt.Sprintf("/intraday/%s?interval=%s&range=%s",
+		url.PathEscape(symbol), url.QueryEscape(interval), url.QueryEscape(dataRange))
+	var buckets []models.OHLCBucket
+	if err := c.get(ctx, path, &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetQuoteHistory fetches up to days of daily quotes for a symbol from
+// the proxy, newest first. Used by the backfill command.
+func (c *ProxyClient) GetQuoteHistory(ctx context.Context, symbol string, days int) ([]models.StockQuote, error) {
+	path := fmt.Sprintf("/history/%s?days=%d", url.PathEscape(symbol), days)
+	var quotes []models.StockQuote
+	if err := c.get(ctx, path, &quotes); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// GetMarketIndex fetches the current value of a market index from the proxy.
+func (c *ProxyClient) GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error) {
+	var index models.MarketIndex
+	if err := c.get(ctx, "/index/"+url.PathEscape(symbol), &index); err != nil {
+		return nil, err
+	}
+	if index.Timestamp.IsZero() {
+		index.Timestamp = time.Now().UTC()
+	}
+	return &index, nil
+}
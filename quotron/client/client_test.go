@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetStockQuoteParsesCryptoFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quote/BTC-USD" {
+			t.Errorf("path = %s, want /quote/BTC-USD", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"symbol": "BTC-USD",
+			"price": 64250.5,
+			"change": 1200.0,
+			"change_percent": 1.9,
+			"volume": 38000000000,
+			"high_24h": 65100.0,
+			"low_24h": 62800.25,
+			"timestamp": "2026-08-27T12:00:00Z",
+			"exchange": "CRYPTO",
+			"source": "coinbase"
+		}`))
+	}))
+	defer srv.Close()
+
+	quote, err := NewProxyClient(srv.URL).GetStockQuote(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.High24h == nil || *quote.High24h != 65100.0 {
+		t.Errorf("High24h = %v, want 65100.0", quote.High24h)
+	}
+	if quote.Low24h == nil || *quote.Low24h != 62800.25 {
+		t.Errorf("Low24h = %v, want 62800.25", quote.Low24h)
+	}
+	if quote.Volume == nil || *quote.Volume != 38000000000 {
+		t.Errorf("Volume = %v, want 38000000000", quote.Volume)
+	}
+}
+
+func TestGetStockQuoteWithout24hRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"symbol": "AAPL", "price": 190.0, "volume": 1000}`))
+	}))
+	defer srv.Close()
+
+	quote, err := NewProxyClient(srv.URL).GetStockQuote(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.High24h != nil || quote.Low24h != nil {
+		t.Errorf("equity quote should leave the 24h range nil, got high=%v low=%v",
+			quote.High24h, quote.Low24h)
+	}
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// flightGroup deduplicates concurrent calls sharing a key, in the
+// spirit of golang.org/x/sync/singleflight, hand-rolled so the module
+// keeps its stdlib-only footprint. The first caller for a key runs the
+// function; callers arriving while it is in flight wait and share the
+// same result.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	waiters int
+	val     interface{}
+	err     error
+}
+
+// do runs fn once per key among concurrent callers. The function runs
+// under the initiating caller's context; a waiter whose own context
+// ends first unblocks with its context error while the call keeps
+// running for the rest.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		c.waiters++
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.val, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+	return c.val, c.err
+}
+
+// ClientHealth is the failover bookkeeping for one upstream source.
+type ClientHealth struct {
+	Name string `json:"name"`
+	// Healthy reports whether the source's most recent call succeeded.
+	Healthy             bool      `json:"healthy"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+	// Breaker is the source's circuit breaker state; an open breaker
+	// means calls are being fast-failed, not attempted.
+	Breaker BreakerState `json:"breaker"`
+}
+
+// ClientManager fronts an ordered list of upstream sources. Each fetch
+// tries them in priority order until one succeeds, failing with an
+// aggregated error only when every source does, and tracks per-source
+// health along the way. It also deduplicates in-flight requests: ten
+// concurrent fetches of a cold symbol — a batch fan-out, the stream
+// bridge, and a dashboard poll landing together — make one upstream
+// call and all receive its result.
+type ClientManager struct {
+	clients  []DataClient
+	breakers []*breaker
+	flights  flightGroup
+
+	mu     sync.Mutex
+	health []ClientHealth
+}
+
+// NewClientManager builds a manager over the given sources, highest
+// priority first. At least one is required. Each source gets a circuit
+// breaker with DefaultBreakerConfig; see SetBreakerConfig.
+func NewClientManager(clients ...DataClient) *ClientManager {
+	if len(clients) == 0 {
+		panic("client: NewClientManager needs at least one DataClient")
+	}
+	health := make([]ClientHealth, len(clients))
+	breakers := make([]*breaker, len(clients))
+	for i, c := range clients {
+		health[i] = ClientHealth{Name: clientName(c), Healthy: true}
+		breakers[i] = newBreaker(DefaultBreakerConfig)
+	}
+	return &ClientManager{clients: clients, breakers: breakers, health: health}
+}
+
+// SetBreakerConfig replaces every source's circuit breaker thresholds.
+func (m *ClientManager) SetBreakerConfig(cfg BreakerConfig) {
+	for i := range m.breakers {
+		m.breakers[i] = newBreaker(cfg)
+	}
+}
+
+// clientName labels a source for health reporting and error prefixes.
+func clientName(c DataClient) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", c), "*")
+}
+
+// GetClientHealth returns a snapshot of every source's health, in
+// priority order.
+func (m *ClientManager) GetClientHealth() []ClientHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	health := append([]ClientHealth{}, m.health...)
+	for i := range health {
+		health[i].Breaker = m.breakers[i].snapshot()
+	}
+	return health
+}
+
+// record updates source i's health after one call.
+func (m *ClientManager) record(i int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := &m.health[i]
+	if err != nil {
+		h.Healthy = false
+		h.ConsecutiveFailures++
+		h.LastError = err.Error()
+		return
+	}
+	h.Healthy = true
+	h.ConsecutiveFailures = 0
+	h.LastError = ""
+	h.LastSuccess = time.Now()
+}
+
+// fetch runs one deduplicated call through the failover chain: sources
+// are tried in order until one succeeds. A dead context stops the chain
+// early rather than charging the remaining sources with failures that
+// are the caller's, not theirs.
+func (m *ClientManager) fetch(ctx context.Context, key string, fn func(DataClient) (interface{}, error)) (interface{}, error) {
+	return m.flights.do(ctx, key, func() (interface{}, error) {
+		var errs []error
+		for i, c := range m.clients {
+			if err := ctx.Err(); err != nil {
+				errs = append(errs, err)
+				break
+			}
+			if !m.breakers[i].allow() {
+				errs = append(errs, fmt.Errorf("%s: circuit open", m.health[i].Name))
+				continue
+			}
+			v, err := fn(c)
+			m.breakers[i].record(err)
+			m.record(i, err)
+			if err == nil {
+				return v, nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", m.health[i].Name, err))
+		}
+		return nil, fmt.Errorf("all %d upstream sources failed: %w", len(m.clients), errors.Join(errs...))
+	})
+}
+
+// GetStockQuote fetches a quote, sharing any in-flight fetch of the
+// same symbol.
+func (m *ClientManager) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	v, err := m.fetch(ctx, "quote:"+symbol, func(c DataClient) (interface{}, error) {
+		return c.GetStockQuote(ctx, symbol)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.StockQuote), nil
+}
+
+// GetMarketIndex fetches an index value, sharing any in-flight fetch of
+// the same symbol.
+func (m *ClientManager) GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error) {
+	v, err := m.fetch(ctx, "index:"+symbol, func(c DataClient) (interface{}, error) {
+		return c.GetMarketIndex(ctx, symbol)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.MarketIndex), nil
+}
+
+// GetIntradaySeries fetches an intraday series, sharing any in-flight
+// fetch of the same symbol, interval, and range.
+func (m *ClientManager) GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error) {
+	key := "intraday:" + symbol + ":" + interval + ":" + dataRange
+	v, err := m.fetch(ctx, key, func(c DataClient) (interface{}, error) {
+		return c.GetIntradaySeries(ctx, symbol, interval, dataRange)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]models.OHLCBucket), nil
+}
+
+// Ping reports reachable when any source with a readiness probe
+// answers; sources without one are assumed reachable.
+func (m *ClientManager) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, c := range m.clients {
+		p, ok := c.(interface{ Ping(context.Context) error })
+		if !ok {
+			return nil
+		}
+		err := p.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return lastErr
+}
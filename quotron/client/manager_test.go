@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// blockingClient counts upstream quote calls and holds each one until
+// release is closed, so a test can pile up concurrent callers.
+type blockingClient struct {
+	staticClient
+	calls   int64
+	release chan struct{}
+}
+
+func (c *blockingClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	atomic.AddInt64(&c.calls, 1)
+	<-c.release
+	return &models.StockQuote{Symbol: symbol, Price: 100}, nil
+}
+
+// waitForWaiters polls until want callers are parked on the key's
+// in-flight call.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		inFlight := ok && c.waiters >= want
+		g.mu.Unlock()
+		if inFlight {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters on %s", want, key)
+}
+
+func TestConcurrentFetchesShareOneUpstreamCall(t *testing.T) {
+	upstream := &blockingClient{release: make(chan struct{})}
+	m := NewClientManager(upstream)
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]*models.StockQuote, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = m.GetStockQuote(context.Background(), "AAPL")
+		}(i)
+	}
+
+	waitForWaiters(t, &m.flights, "quote:AAPL", n-1)
+	close(upstream.release)
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&upstream.calls); got != 1 {
+		t.Fatalf("upstream calls = %d, want 1", got)
+	}
+	for i, q := range results {
+		if q == nil || q.Symbol != "AAPL" {
+			t.Errorf("caller %d got %+v, want the shared AAPL quote", i, q)
+		}
+	}
+}
+
+func TestDifferentSymbolsFetchIndependently(t *testing.T) {
+	upstream := &blockingClient{release: make(chan struct{})}
+	close(upstream.release) // no blocking needed here
+	m := NewClientManager(upstream)
+
+	if _, err := m.GetStockQuote(context.Background(), "AAPL"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetStockQuote(context.Background(), "MSFT"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt64(&upstream.calls); got != 2 {
+		t.Errorf("upstream calls = %d, want 2", got)
+	}
+}
+
+func TestWaiterHonorsItsOwnContext(t *testing.T) {
+	upstream := &blockingClient{release: make(chan struct{})}
+	defer close(upstream.release)
+	m := NewClientManager(upstream)
+
+	go m.GetStockQuote(context.Background(), "AAPL")
+	waitForWaiters(t, &m.flights, "quote:AAPL", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.GetStockQuote(ctx, "AAPL"); err != context.Canceled {
+		t.Errorf("canceled waiter got %v, want context.Canceled", err)
+	}
+}
+
+// failingClient errors on every call.
+type failingClient struct {
+	staticClient
+	err error
+}
+
+func (c *failingClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.staticClient.GetStockQuote(ctx, symbol)
+}
+
+func TestFailoverTriesSourcesInOrder(t *testing.T) {
+	primary := &failingClient{err: errGone}
+	m := NewClientManager(primary, staticClient{price: 42})
+
+	quote, err := m.GetStockQuote(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Price != 42 {
+		t.Errorf("price = %v, want the secondary's 42", quote.Price)
+	}
+
+	health := m.GetClientHealth()
+	if len(health) != 2 {
+		t.Fatalf("got %d health entries, want 2", len(health))
+	}
+	if health[0].Healthy || health[0].ConsecutiveFailures != 1 {
+		t.Errorf("primary health = %+v, want unhealthy with 1 failure", health[0])
+	}
+	if !health[1].Healthy || health[1].LastSuccess.IsZero() {
+		t.Errorf("secondary health = %+v, want healthy with a success time", health[1])
+	}
+}
+
+func TestFailoverAggregatesAllErrors(t *testing.T) {
+	m := NewClientManager(
+		&failingClient{err: errGone},
+		&failingClient{err: errAlsoGone},
+	)
+
+	_, err := m.GetStockQuote(context.Background(), "AAPL")
+	if err == nil {
+		t.Fatal("want an error when every source fails")
+	}
+	for _, want := range []string{"all 2 upstream sources failed", errGone.Error(), errAlsoGone.Error()} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+}
+
+func TestRecoveryResetsHealth(t *testing.T) {
+	primary := &failingClient{err: errGone}
+	secondary := &blockingClient{release: make(chan struct{})}
+	close(secondary.release)
+	m := NewClientManager(primary, secondary)
+
+	m.GetStockQuote(context.Background(), "AAPL")
+	m.GetStockQuote(context.Background(), "AAPL")
+	if h := m.GetClientHealth()[0]; h.ConsecutiveFailures != 2 {
+		t.Errorf("primary failures = %d, want 2", h.ConsecutiveFailures)
+	}
+
+	primary.err = nil
+	m.GetStockQuote(context.Background(), "AAPL")
+	if h := m.GetClientHealth()[0]; !h.Healthy || h.ConsecutiveFailures != 0 || h.LastError != "" {
+		t.Errorf("recovered primary health = %+v, want clean", h)
+	}
+}
+
+var (
+	errGone     = errors.New("rate limited")
+	errAlsoGone = errors.New("connection refused")
+)
+
+func BenchmarkDeduplicatedFetch(b *testing.B) {
+	m := NewClientManager(staticClient{price: 100})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.GetStockQuote(context.Background(), "AAPL")
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory builds a DataClient from its config string. What the string
+// means is up to the source — the proxy takes its base URL; a custom
+// feed might take a DSN or a file path.
+type Factory func(config string) (DataClient, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// RegisterClient makes a data source selectable by name, so proprietary
+// feeds plug in without touching core code. It panics on a duplicate or
+// empty name, mirroring database/sql.Register: both are programmer
+// errors best caught at init time.
+func RegisterClient(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if name == "" || factory == nil {
+		panic("client: RegisterClient needs a name and a factory")
+	}
+	if _, dup := factories[name]; dup {
+		panic("client: RegisterClient called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// NewDataClient builds the registered client for name.
+func NewDataClient(name, config string) (DataClient, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown data client %q (registered: %v)", name, registeredNames())
+	}
+	return factory(config)
+}
+
+func registeredNames() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterClient("proxy", func(config string) (DataClient, error) {
+		return NewProxyClient(config), nil
+	})
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// staticClient is a custom data source serving one fixed quote.
+type staticClient struct{ price float64 }
+
+func (c staticClient) GetStockQuote(ctx context.Context, symbol string) (*models.StockQuote, error) {
+	return &models.StockQuote{Symbol: symbol, Price: c.price}, nil
+}
+func (c staticClient) GetMarketIndex(ctx context.Context, symbol string) (*models.MarketIndex, error) {
+	return &models.MarketIndex{Symbol: symbol, Value: c.price}, nil
+}
+func (c staticClient) GetIntradaySeries(ctx context.Context, symbol, interval, dataRange string) ([]models.OHLCBucket, error) {
+	return nil, nil
+}
+
+func TestRegisterAndSelectCustomClient(t *testing.T) {
+	RegisterClient("static-test", func(config string) (DataClient, error) {
+		return staticClient{price: 42}, nil
+	})
+
+	c, err := NewDataClient("static-test", "")
+	if err != nil {
+		t.Fatalf("NewDataClient: %v", err)
+	}
+	quote, err := c.GetStockQuote(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Price != 42 {
+		t.Errorf("price = %v, want the custom client's 42", quote.Price)
+	}
+}
+
+func TestProxyClientIsRegisteredByDefault(t *testing.T) {
+	c, err := NewDataClient("proxy", "http://localhost:8001")
+	if err != nil {
+		t.Fatalf("NewDataClient: %v", err)
+	}
+	if _, ok := c.(*ProxyClient); !ok {
+		t.Errorf("proxy factory built %T, want *ProxyClient", c)
+	}
+}
+
+func TestUnknownClientNamesRegistered(t *testing.T) {
+	_, err := NewDataClient("bloomberg", "")
+	if err == nil || !strings.Contains(err.Error(), "proxy") {
+		t.Errorf("err = %v, want it to list the registered names", err)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the same name twice should panic")
+		}
+	}()
+	RegisterClient("proxy", func(config string) (DataClient, error) { return nil, nil })
+}
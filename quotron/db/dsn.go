@@ -0,0 +1,104 @@
+// Package db holds the database plumbing shared by every service:
+// connection-string building and, over time, the rest of the common
+// Postgres glue.
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// SSLConfig is how a service asks for transport security on its DB
+// connection. Mode follows the libpq sslmode values; RootCert is the CA
+// certificate path for the verify-* modes.
+type SSLConfig struct {
+	Mode     string
+	RootCert string
+}
+
+// sslModes are the supported libpq sslmode values.
+var sslModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// Validate rejects unknown modes and verify-* modes without a CA cert.
+func (c SSLConfig) Validate() error {
+	if c.Mode == "" {
+		return nil
+	}
+	if !sslModes[c.Mode] {
+		return fmt.Errorf("invalid sslmode %q (want disable, require, verify-ca, or verify-full)", c.Mode)
+	}
+	if (c.Mode == "verify-ca" || c.Mode == "verify-full") && c.RootCert == "" {
+		return fmt.Errorf("sslmode %s requires a CA certificate path", c.Mode)
+	}
+	return nil
+}
+
+// BuildDSN assembles a postgres:// connection URL, escaping each part so
+// passwords (or users, or database names) containing special characters
+// survive. Prefer this over hand-rolled fmt.Sprintf DSNs.
+func BuildDSN(host, port, user, pass, dbname, sslmode string) string {
+	return BuildDSNWithSSL(host, port, user, pass, dbname, SSLConfig{Mode: sslmode})
+}
+
+// BuildDSNWithSSL is BuildDSN with full SSL configuration, including the
+// CA certificate for verify-ca / verify-full.
+func BuildDSNWithSSL(host, port, user, pass, dbname string, ssl SSLConfig) string {
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   host,
+		Path:   "/" + dbname,
+	}
+	if port != "" {
+		u.Host = host + ":" + port
+	}
+	if user != "" {
+		if pass != "" {
+			u.User = url.UserPassword(user, pass)
+		} else {
+			u.User = url.User(user)
+		}
+	}
+	q := url.Values{}
+	if ssl.Mode != "" {
+		q.Set("sslmode", ssl.Mode)
+	}
+	if ssl.RootCert != "" {
+		q.Set("sslrootcert", ssl.RootCert)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// BuildDSNFromEnv builds a DSN from the conventional DB_* environment
+// variables, returning "" when DB_HOST is unset so callers can fall back
+// to DATABASE_URL.
+func BuildDSNFromEnv() string {
+	host := os.Getenv("DB_HOST")
+	if host == "" {
+		return ""
+	}
+	return BuildDSNWithSSL(
+		host,
+		envOr("DB_PORT", "5432"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		envOr("DB_NAME", "quotron"),
+		SSLConfig{
+			Mode:     envOr("DB_SSLMODE", "disable"),
+			RootCert: os.Getenv("DB_SSLROOTCERT"),
+		},
+	)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultListLimit caps a quote listing when the caller does not ask for
+// a limit, so an unfiltered list cannot drag the whole table back.
+const DefaultListLimit = 100
+
+// QuoteFilter narrows a quote listing. Zero values mean "no filter": an
+// empty slice matches every value, a zero Since means no time bound, and
+// Limit <= 0 falls back to DefaultListLimit.
+type QuoteFilter struct {
+	Symbols   []string
+	Exchanges []string
+	Sources   []string
+	Since     time.Time
+	Limit     int
+	Offset    int
+}
+
+// BuildQuoteQuery renders a filter into a parameterized SELECT over
+// stock_quotes. Every caller-supplied value travels as a $n argument —
+// nothing from the filter is ever spliced into the SQL text — so the
+// query stays safe no matter what the values contain.
+func BuildQuoteQuery(f QuoteFilter) (string, []interface{}) {
+	var (
+		sb    strings.Builder
+		args  []interface{}
+		conds []string
+	)
+	sb.WriteString(`SELECT symbol, price, change, change_percent, volume, high_24h, low_24h, timestamp, exchange, source
+FROM stock_quotes`)
+
+	if len(f.Symbols) > 0 {
+		conds = append(conds, inClause("symbol", f.Symbols, &args))
+	}
+	if len(f.Exchanges) > 0 {
+		conds = append(conds, inClause("exchange", f.Exchanges, &args))
+	}
+	if len(f.Sources) > 0 {
+		conds = append(conds, inClause("source", f.Sources, &args))
+	}
+	if !f.Since.IsZero() {
+		args = append(args, f.Since)
+		conds = append(conds, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+	if len(conds) > 0 {
+		sb.WriteString("\nWHERE " + strings.Join(conds, " AND "))
+	}
+
+	sb.WriteString("\nORDER BY timestamp DESC")
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	args = append(args, limit)
+	fmt.Fprintf(&sb, "\nLIMIT $%d", len(args))
+
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		fmt.Fprintf(&sb, "\nOFFSET $%d", len(args))
+	}
+	return sb.String(), args
+}
+
+// inClause renders `col IN ($i, $i+1, ...)`, appending each value to
+// args so the placeholder numbers line up with the argument positions.
+func inClause(col string, values []string, args *[]interface{}) string {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		*args = append(*args, v)
+		placeholders[i] = fmt.Sprintf("$%d", len(*args))
+	}
+	return col + " IN (" + strings.Join(placeholders, ", ") + ")"
+}
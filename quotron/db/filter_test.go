@@ -0,0 +1,84 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var placeholderRE = regexp.MustCompile(`\$\d+`)
+
+// Every placeholder in the SQL must have exactly one argument, whatever
+// combination of filters is set.
+func TestBuildQuoteQueryPlaceholdersMatchArgs(t *testing.T) {
+	since := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		filter QuoteFilter
+	}{
+		{"empty", QuoteFilter{}},
+		{"one symbol", QuoteFilter{Symbols: []string{"AAPL"}}},
+		{"many symbols", QuoteFilter{Symbols: []string{"AAPL", "MSFT", "GOOG"}}},
+		{"everything", QuoteFilter{
+			Symbols:   []string{"AAPL", "MSFT"},
+			Exchanges: []string{"NASDAQ"},
+			Sources:   []string{"api-scraper", "backfill"},
+			Since:     since,
+			Limit:     50,
+			Offset:    100,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args := BuildQuoteQuery(tc.filter)
+			placeholders := placeholderRE.FindAllString(query, -1)
+			if len(placeholders) != len(args) {
+				t.Fatalf("%d placeholders for %d args:\n%s", len(placeholders), len(args), query)
+			}
+			seen := map[string]bool{}
+			for i, ph := range placeholders {
+				if want := "$" + string(rune('1'+i)); len(args) < 10 && ph != want {
+					t.Errorf("placeholder %d is %s, want %s", i, ph, want)
+				}
+				if seen[ph] {
+					t.Errorf("placeholder %s used twice", ph)
+				}
+				seen[ph] = true
+			}
+		})
+	}
+}
+
+// Filter values must never appear in the SQL text itself; that is the
+// whole point of the builder.
+func TestBuildQuoteQueryNeverInlinesValues(t *testing.T) {
+	hostile := "'; DROP TABLE stock_quotes; --"
+	query, args := BuildQuoteQuery(QuoteFilter{
+		Symbols:   []string{hostile},
+		Exchanges: []string{hostile},
+		Sources:   []string{hostile},
+	})
+	if strings.Contains(query, "DROP TABLE") {
+		t.Fatalf("filter value leaked into SQL:\n%s", query)
+	}
+	if len(args) != 4 { // three values plus the default limit
+		t.Errorf("got %d args, want 4", len(args))
+	}
+}
+
+func TestBuildQuoteQueryDefaults(t *testing.T) {
+	query, args := BuildQuoteQuery(QuoteFilter{})
+	if strings.Contains(query, "WHERE") {
+		t.Errorf("empty filter should have no WHERE clause:\n%s", query)
+	}
+	if !strings.Contains(query, "ORDER BY timestamp DESC") {
+		t.Errorf("listing must be newest first:\n%s", query)
+	}
+	if len(args) != 1 || args[0] != DefaultListLimit {
+		t.Errorf("args = %v, want just the default limit %d", args, DefaultListLimit)
+	}
+	if strings.Contains(query, "OFFSET") {
+		t.Errorf("zero offset should not emit OFFSET:\n%s", query)
+	}
+}
@@ -0,0 +1,17 @@
+// Package events names the Redis streams and consumer groups the
+// services share. Producers and consumers must agree on these exactly —
+// a publisher on "quotron:stocks" and a consumer on
+// "quotron:stocks:stream" silently miss each other — so the names live
+// here once instead of as literals on each side.
+package events
+
+// AlertStream carries price-move alerts from the producers to the agent.
+const AlertStream = "quotron:alerts"
+
+// AlertGroup is the consumer group the agent instances share, so several
+// agents split the alert stream instead of each processing everything.
+const AlertGroup = "agent"
+
+// HealthStream carries job health transitions (healthy, degraded,
+// failed) from the scheduler to whatever wants to alert on them.
+const HealthStream = "quotron:health:events"
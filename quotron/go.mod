@@ -0,0 +1,3 @@
+module github.com/we-be/tiny-ria/quotron
+
+go 1.21
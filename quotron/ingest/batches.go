@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// BatchState is the lifecycle of one ingested batch in data_batches.
+type BatchState string
+
+const (
+	// BatchProcessing is written before the sink runs, so a crash
+	// mid-batch leaves an honest record instead of silence.
+	BatchProcessing BatchState = "processing"
+	BatchCompleted  BatchState = "completed"
+	BatchFailed     BatchState = "failed"
+	// BatchPartial marks a batch where some rows landed and the rest
+	// did not; only sinks that can tell the difference report it.
+	BatchPartial BatchState = "partial"
+)
+
+// BatchRecord is one row of the data_batches bookkeeping table.
+type BatchRecord struct {
+	ID         string     `json:"id"`
+	Status     BatchState `json:"status"`
+	QuoteCount int        `json:"quote_count"`
+	IndexCount int        `json:"index_count"`
+	Error      string     `json:"error,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// RecordBatch upserts one batch row, so the same ID moves through its
+// states (processing, then completed/failed) as a single row.
+func RecordBatch(ctx context.Context, db *sql.DB, rec BatchRecord) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO data_batches (id, status, quote_count, index_count, error, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			quote_count = EXCLUDED.quote_count,
+			index_count = EXCLUDED.index_count,
+			error = EXCLUDED.error,
+			updated_at = now()`,
+		rec.ID, string(rec.Status), rec.QuoteCount, rec.IndexCount, rec.Error)
+	return err
+}
+
+// GetBatchRecord loads one batch row; sql.ErrNoRows when the ID is
+// unknown (or already aged out of the table).
+func GetBatchRecord(ctx context.Context, db *sql.DB, id string) (*BatchRecord, error) {
+	var rec BatchRecord
+	var batchErr sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT id, status, quote_count, index_count, error, updated_at
+		FROM data_batches
+		WHERE id = $1`, id).
+		Scan(&rec.ID, &rec.Status, &rec.QuoteCount, &rec.IndexCount, &batchErr, &rec.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	rec.Error = batchErr.String
+	return &rec, nil
+}
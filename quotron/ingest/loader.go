@@ -0,0 +1,74 @@
+// Package ingest is the ETL layer: it loads raw JSON payloads produced
+// by the scrapers, optionally validates them against the generated
+// finance schema, and accumulates them into batches for storage.
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// Loader parses raw scraper payloads into model types.
+type Loader struct {
+	// schema is non-nil when payloads should be validated before parsing.
+	schema *Schema
+}
+
+// NewLoader builds a Loader. With validate set, payloads are checked
+// against the generated finance schema and structurally-invalid input is
+// rejected before any parsing happens.
+func NewLoader(validate bool) (*Loader, error) {
+	l := &Loader{}
+	if validate {
+		schema, err := LoadSchema(models.FinanceSchemaJSON)
+		if err != nil {
+			return nil, fmt.Errorf("loading finance schema: %w", err)
+		}
+		l.schema = schema
+	}
+	return l, nil
+}
+
+// LoadStockQuotes parses a JSON array of stock quotes.
+func (l *Loader) LoadStockQuotes(data []byte) ([]models.StockQuote, error) {
+	if err := l.validateEach("stock_quote", data); err != nil {
+		return nil, err
+	}
+	var quotes []models.StockQuote
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return nil, fmt.Errorf("parsing stock quotes: %w", err)
+	}
+	return quotes, nil
+}
+
+// LoadMarketIndices parses a JSON array of market index values.
+func (l *Loader) LoadMarketIndices(data []byte) ([]models.MarketIndex, error) {
+	if err := l.validateEach("market_index", data); err != nil {
+		return nil, err
+	}
+	var indices []models.MarketIndex
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return nil, fmt.Errorf("parsing market indices: %w", err)
+	}
+	return indices, nil
+}
+
+// validateEach checks every element of a JSON array against the named
+// schema definition. A no-op when validation is disabled.
+func (l *Loader) validateEach(defName string, data []byte) error {
+	if l.schema == nil {
+		return nil
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return fmt.Errorf("payload is not a JSON array: %w", err)
+	}
+	for i, elem := range elems {
+		if err := l.schema.Validate(defName, elem); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/storage"
+)
+
+// migrationTimeout bounds a single migration file. Generous because
+// index builds on large tables take a while, but finite so a lost
+// connection doesn't hang the runner forever.
+const migrationTimeout = 5 * time.Minute
+
+// migrationSource is where migration SQL is read from: the FS embedded
+// in the binary by default, so the runner works no matter where (or
+// from where) it is started. --migrations-dir swaps in external files.
+var migrationSource fs.FS = mustSubFS(storage.Migrations, "migrations")
+
+func mustSubFS(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// SetMigrationsDir points the runner at an external migration directory
+// instead of the embedded files, for ad-hoc testing of unreleased SQL.
+func SetMigrationsDir(dir string) {
+	migrationSource = os.DirFS(dir)
+}
+
+// MigrationFiles returns the forward migration filenames in apply
+// order. The _down.sql counterparts are excluded; RollbackMigrations
+// uses those.
+func MigrationFiles() ([]string, error) {
+	entries, err := fs.Glob(migrationSource, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range entries {
+		if !strings.HasSuffix(name, "_down.sql") {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// downFile maps a forward migration filename to its _down.sql name.
+func downFile(name string) string {
+	return strings.TrimSuffix(name, ".sql") + "_down.sql"
+}
+
+// RunMigrations applies every migration file not yet recorded in the
+// schema_migrations table, in order, and returns the filenames it
+// applied. Each migration runs in its own transaction together with its
+// bookkeeping row, so a half-applied file is rolled back and retried on
+// the next run instead of being recorded as done.
+func RunMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	files, err := MigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []string
+	for _, name := range files {
+		if applied[name] {
+			continue
+		}
+		ddl, err := fs.ReadFile(migrationSource, name)
+		if err != nil {
+			return ran, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := applyMigration(ctx, db, name, string(ddl)); err != nil {
+			return ran, fmt.Errorf("applying %s: %w", name, err)
+		}
+		ran = append(ran, name)
+	}
+	return ran, nil
+}
+
+// RollbackMigrations undoes the last n applied migrations in reverse
+// order by running each one's _down.sql, removing its schema_migrations
+// row in the same transaction. A migration without a down file stops the
+// rollback before anything newer than it has been kept.
+func RollbackMigrations(ctx context.Context, db *sql.DB, n int) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("rollback count must be positive, got %d", n)
+	}
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := lastApplied(ctx, db, n)
+	if err != nil {
+		return nil, err
+	}
+
+	var undone []string
+	for _, name := range applied {
+		ddl, err := fs.ReadFile(migrationSource, downFile(name))
+		if err != nil {
+			return undone, fmt.Errorf("%s has no down migration: %w", name, err)
+		}
+		if err := applyRollback(ctx, db, name, string(ddl)); err != nil {
+			return undone, fmt.Errorf("rolling back %s: %w", name, err)
+		}
+		undone = append(undone, name)
+	}
+	return undone, nil
+}
+
+// lastApplied returns the most recently applied migration filenames,
+// newest first. Filename order is apply order, so it doubles as the
+// rollback order reversed.
+func lastApplied(ctx context.Context, db *sql.DB, n int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT filename FROM schema_migrations ORDER BY filename DESC LIMIT $1", n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// applyRollback runs one down migration and deletes its bookkeeping row
+// in a single transaction, under its own timeout.
+func applyRollback(ctx context.Context, db *sql.DB, name, ddl string) error {
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, ddl); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE filename = $1", name); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureMigrationsTable creates the bookkeeping table recording which
+// migration files have been applied.
+func ensureMigrationsTable(ctx context.Context, db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		filename   TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	return err
+}
+
+// appliedMigrations returns the filenames already recorded as applied.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SELECT filename FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		applied[name] = true
+	}
+	return applied, rows.Err()
+}
+
+// applyMigration runs one migration file and its bookkeeping insert in a
+// single transaction, under its own timeout.
+func applyMigration(ctx context.Context, db *sql.DB, name, ddl string) error {
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, ddl); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (filename) VALUES ($1)", name); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
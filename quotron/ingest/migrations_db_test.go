@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// migDriver is a stub database/sql driver recording executed statements
+// and serving a configurable schema_migrations table, so the tracking
+// logic is testable without Postgres.
+type migDriver struct{}
+
+var migState = struct {
+	sync.Mutex
+	applied  []string // rows served for SELECT filename FROM schema_migrations
+	execs    []string
+	execArgs [][]driver.Value
+}{}
+
+func resetMigState(applied ...string) {
+	migState.Lock()
+	defer migState.Unlock()
+	migState.applied = applied
+	migState.execs = nil
+	migState.execArgs = nil
+}
+
+func migExecs() []string {
+	migState.Lock()
+	defer migState.Unlock()
+	return append([]string(nil), migState.execs...)
+}
+
+func (migDriver) Open(name string) (driver.Conn, error) { return migConn{}, nil }
+
+type migConn struct{}
+
+func (migConn) Prepare(query string) (driver.Stmt, error) { return nil, io.EOF }
+func (migConn) Close() error                              { return nil }
+func (migConn) Begin() (driver.Tx, error)                 { return migTx{}, nil }
+
+type migTx struct{}
+
+func (migTx) Commit() error   { return nil }
+func (migTx) Rollback() error { return nil }
+
+func (migConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	migState.Lock()
+	defer migState.Unlock()
+	migState.execs = append(migState.execs, query)
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	migState.execArgs = append(migState.execArgs, values)
+	return driver.RowsAffected(1), nil
+}
+
+func (migConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	migState.Lock()
+	defer migState.Unlock()
+	return &appliedRows{rows: append([]string(nil), migState.applied...)}, nil
+}
+
+type appliedRows struct {
+	rows []string
+	i    int
+}
+
+func (r *appliedRows) Columns() []string { return []string{"filename"} }
+func (r *appliedRows) Close() error      { return nil }
+func (r *appliedRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	dest[0] = r.rows[r.i]
+	r.i++
+	return nil
+}
+
+func init() {
+	sql.Register("migtest", migDriver{})
+}
+
+func TestRunMigrationsSkipsRecordedFiles(t *testing.T) {
+	files, err := MigrationFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := filepath.Base(files[0])
+	resetMigState(first)
+
+	db, err := sql.Open("migtest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran, err := RunMigrations(context.Background(), db)
+	if err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	if len(ran) != len(files)-1 {
+		t.Fatalf("ran %d migrations (%v), want %d", len(ran), ran, len(files)-1)
+	}
+	for _, name := range ran {
+		if name == first {
+			t.Errorf("%s was already recorded but ran again", first)
+		}
+	}
+	for i := 1; i < len(ran); i++ {
+		if ran[i-1] >= ran[i] {
+			t.Errorf("migrations applied out of order: %s before %s", ran[i-1], ran[i])
+		}
+	}
+}
+
+func TestRunMigrationsRecordsEachFile(t *testing.T) {
+	resetMigState()
+	db, err := sql.Open("migtest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran, err := RunMigrations(context.Background(), db)
+	if err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	var inserts int
+	for _, q := range migExecs() {
+		if strings.Contains(q, "INSERT INTO schema_migrations") {
+			inserts++
+		}
+	}
+	if inserts != len(ran) {
+		t.Errorf("bookkeeping inserts = %d, want one per applied migration (%d)", inserts, len(ran))
+	}
+}
+
+func TestRollbackRunsDownFilesNewestFirst(t *testing.T) {
+	// The stub serves applied rows as configured; list the two newest
+	// migrations newest-first, as the ORDER BY DESC query would.
+	resetMigState("004_notify_quote_inserts.sql", "003_unique_quote_timestamp.sql")
+
+	db, err := sql.Open("migtest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	undone, err := RollbackMigrations(context.Background(), db, 2)
+	if err != nil {
+		t.Fatalf("RollbackMigrations: %v", err)
+	}
+	if len(undone) != 2 || undone[0] != "004_notify_quote_inserts.sql" || undone[1] != "003_unique_quote_timestamp.sql" {
+		t.Fatalf("undone = %v, want the two newest, newest first", undone)
+	}
+
+	var drops, deletes int
+	for _, q := range migExecs() {
+		if strings.Contains(q, "DROP ") {
+			drops++
+		}
+		if strings.Contains(q, "DELETE FROM schema_migrations") {
+			deletes++
+		}
+	}
+	if drops < 2 || deletes != 2 {
+		t.Errorf("executed %d DROP statements and %d bookkeeping deletes, want >=2 and 2", drops, deletes)
+	}
+}
+
+func TestRollbackFailsWithoutDownFile(t *testing.T) {
+	resetMigState("999_no_down_file.sql")
+
+	db, err := sql.Open("migtest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RollbackMigrations(context.Background(), db, 1); err == nil ||
+		!strings.Contains(err.Error(), "no down migration") {
+		t.Errorf("err = %v, want a missing down migration error", err)
+	}
+}
+
+func TestRunMigrationsIsANoOpWhenAllRecorded(t *testing.T) {
+	files, err := MigrationFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	resetMigState(names...)
+
+	db, err := sql.Open("migtest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran, err := RunMigrations(context.Background(), db)
+	if err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("ran %v, want nothing on an up-to-date database", ran)
+	}
+}
@@ -0,0 +1,72 @@
+package ingest
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrationFilesOrdered(t *testing.T) {
+	files, err := MigrationFiles()
+	if err != nil {
+		t.Fatalf("MigrationFiles: %v", err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("got %d migration files, want at least 2", len(files))
+	}
+	for i := 1; i < len(files); i++ {
+		if files[i-1] >= files[i] {
+			t.Errorf("migrations out of order: %s before %s", files[i-1], files[i])
+		}
+	}
+}
+
+func TestMigrationsDirOverride(t *testing.T) {
+	prev := migrationSource
+	t.Cleanup(func() { migrationSource = prev })
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "900_custom.sql"), []byte("SELECT 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetMigrationsDir(dir)
+
+	files, err := MigrationFiles()
+	if err != nil {
+		t.Fatalf("MigrationFiles: %v", err)
+	}
+	if len(files) != 1 || files[0] != "900_custom.sql" {
+		t.Errorf("files = %v, want just the external migration", files)
+	}
+}
+
+// The history queries depend on the composite indexes from migration 002;
+// assert the migration actually creates them.
+func TestQuoteHistoryIndexesPresent(t *testing.T) {
+	files, err := MigrationFiles()
+	if err != nil {
+		t.Fatalf("MigrationFiles: %v", err)
+	}
+
+	var ddl strings.Builder
+	for _, file := range files {
+		data, err := fs.ReadFile(migrationSource, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ddl.Write(data)
+	}
+
+	for _, want := range []string{
+		"idx_stock_quotes_symbol_timestamp",
+		"ON stock_quotes (symbol, timestamp DESC)",
+		"idx_market_indices_symbol_timestamp",
+		"ON market_indices (symbol, timestamp DESC)",
+	} {
+		if !strings.Contains(ddl.String(), want) {
+			t.Errorf("migrations missing %q", want)
+		}
+	}
+}
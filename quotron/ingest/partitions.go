@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Monthly partitioning support for stock_quotes. Only relevant after the
+// optional partitioning migration has been applied; on unpartitioned
+// deployments none of this is called.
+
+// PartitionName returns the partition table holding quotes for the month
+// containing t, e.g. stock_quotes_y2025m01.
+func PartitionName(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("stock_quotes_y%04dm%02d", t.Year(), int(t.Month()))
+}
+
+// PartitionBounds returns the [from, to) month range for the partition
+// containing t.
+func PartitionBounds(t time.Time) (from, to time.Time) {
+	t = t.UTC()
+	from = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, 0)
+}
+
+// partitionDDL builds the CREATE TABLE statement for one month partition.
+func partitionDDL(month time.Time) string {
+	from, to := PartitionBounds(month)
+	return fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF stock_quotes FOR VALUES FROM ('%s') TO ('%s')",
+		PartitionName(month),
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// partitionTimeout bounds the DDL for one partition; creating an empty
+// partition is fast, so a hung statement means connection trouble.
+const partitionTimeout = 30 * time.Second
+
+// CreatePartition creates the partition for the month containing t.
+func CreatePartition(ctx context.Context, db *sql.DB, t time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, partitionTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, partitionDDL(t)); err != nil {
+		return fmt.Errorf("creating partition %s: %w", PartitionName(t), err)
+	}
+	return nil
+}
+
+// EnsureFuturePartitions creates partitions for the current month and
+// the next monthsAhead months, so inserts never land in the default
+// catch-all. Intended to run from a scheduled job.
+func EnsureFuturePartitions(ctx context.Context, db *sql.DB, monthsAhead int) error {
+	month, _ := PartitionBounds(time.Now())
+	for i := 0; i <= monthsAhead; i++ {
+		if err := CreatePartition(ctx, db, month); err != nil {
+			return err
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+	return nil
+}
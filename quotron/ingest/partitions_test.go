@@ -0,0 +1,49 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPartitionName(t *testing.T) {
+	ts := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	if got := PartitionName(ts); got != "stock_quotes_y2025m01" {
+		t.Errorf("PartitionName = %q", got)
+	}
+}
+
+func TestPartitionBoundsMonthRollover(t *testing.T) {
+	ts := time.Date(2024, time.December, 31, 23, 59, 59, 0, time.UTC)
+	from, to := PartitionBounds(ts)
+	if !from.Equal(time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("from = %v", from)
+	}
+	if !to.Equal(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("to = %v", to)
+	}
+}
+
+func TestPartitionBoundsRouteDistinctMonths(t *testing.T) {
+	// A timestamp at the very end of one month and the start of the next
+	// must route to different partitions.
+	endOfJan := time.Date(2025, time.January, 31, 23, 59, 59, 0, time.UTC)
+	startOfFeb := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	if PartitionName(endOfJan) == PartitionName(startOfFeb) {
+		t.Error("adjacent months share a partition")
+	}
+}
+
+func TestPartitionDDL(t *testing.T) {
+	ts := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	ddl := partitionDDL(ts)
+	for _, want := range []string{
+		"stock_quotes_y2025m03",
+		"PARTITION OF stock_quotes",
+		"FROM ('2025-03-01') TO ('2025-04-01')",
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL %q missing %q", ddl, want)
+		}
+	}
+}
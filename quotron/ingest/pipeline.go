@@ -0,0 +1,209 @@
+package ingest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// QuoteSink receives flushed batches from the Pipeline. The storage layer
+// implements this; tests use a function adapter.
+type QuoteSink interface {
+	StoreQuotes(ctx context.Context, quotes []models.StockQuote) error
+}
+
+// QuoteSinkFunc adapts a function to the QuoteSink interface.
+type QuoteSinkFunc func(ctx context.Context, quotes []models.StockQuote) error
+
+// StoreQuotes calls f.
+func (f QuoteSinkFunc) StoreQuotes(ctx context.Context, quotes []models.StockQuote) error {
+	return f(ctx, quotes)
+}
+
+// defaultBatchRetention is how long a flushed batch's status stays
+// queryable. Long enough for a caller to poll after a big load, short
+// enough that a long-running service does not accumulate them forever.
+const defaultBatchRetention = time.Hour
+
+// BatchStatus is the recorded outcome of one flushed batch.
+type BatchStatus struct {
+	ID      string
+	Quotes  int
+	Error   string // empty when the sink accepted the batch
+	Flushed time.Time
+}
+
+// Pipeline accumulates parsed quotes into batches and flushes them to a
+// sink once the batch size is reached. Safe for concurrent use; each
+// flush is recorded under a batch ID so callers can look up what
+// happened to their data afterwards.
+type Pipeline struct {
+	sink      QuoteSink
+	batchSize int
+	retention time.Duration
+
+	mu       sync.Mutex
+	batch    []models.StockQuote
+	statuses map[string]BatchStatus
+	statusDB *sql.DB          // optional: persist statuses to data_batches
+	now      func() time.Time // swapped in tests
+}
+
+// NewPipeline builds a Pipeline flushing to sink every batchSize quotes.
+func NewPipeline(sink QuoteSink, batchSize int) *Pipeline {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	return &Pipeline{
+		sink:      sink,
+		batchSize: batchSize,
+		retention: defaultBatchRetention,
+		statuses:  map[string]BatchStatus{},
+		now:       time.Now,
+	}
+}
+
+// SetStatusDB makes the pipeline persist each batch's lifecycle to the
+// data_batches table (migration 006), so status survives a restart and
+// other services can serve it. Persistence failures are logged, never
+// allowed to fail the flush itself.
+func (p *Pipeline) SetStatusDB(db *sql.DB) {
+	p.mu.Lock()
+	p.statusDB = db
+	p.mu.Unlock()
+}
+
+// SetRetention overrides how long flushed batch statuses stay queryable.
+func (p *Pipeline) SetRetention(d time.Duration) {
+	p.mu.Lock()
+	p.retention = d
+	p.mu.Unlock()
+}
+
+// Add appends a quote to the current batch, flushing when full. It
+// returns the batch ID when the add triggered a flush, "" otherwise.
+func (p *Pipeline) Add(ctx context.Context, quote models.StockQuote) (string, error) {
+	p.mu.Lock()
+	p.batch = append(p.batch, quote)
+	full := len(p.batch) >= p.batchSize
+	p.mu.Unlock()
+	if full {
+		return p.Flush(ctx)
+	}
+	return "", nil
+}
+
+// Flush writes any buffered quotes to the sink and records the outcome
+// under a fresh batch ID, which it returns ("" when nothing was
+// buffered). The sink error is both returned and kept in the status.
+func (p *Pipeline) Flush(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	if len(p.batch) == 0 {
+		p.mu.Unlock()
+		return "", nil
+	}
+	batch := p.batch
+	p.batch = nil
+	p.mu.Unlock()
+
+	id := newBatchID()
+	p.persistStatus(ctx, BatchRecord{ID: id, Status: BatchProcessing, QuoteCount: len(batch)})
+	err := p.storeQuotes(ctx, batch)
+
+	status := BatchStatus{ID: id, Quotes: len(batch), Flushed: p.now()}
+	record := BatchRecord{ID: id, Status: BatchCompleted, QuoteCount: len(batch)}
+	if err != nil {
+		status.Error = err.Error()
+		record.Status = BatchFailed
+		record.Error = err.Error()
+	}
+	p.persistStatus(ctx, record)
+
+	p.mu.Lock()
+	p.sweepLocked()
+	p.statuses[id] = status
+	p.mu.Unlock()
+	return id, err
+}
+
+// storeQuotes runs the sink under a panic guard: a panicking sink marks
+// its batch failed instead of taking down whatever worker flushed it.
+func (p *Pipeline) storeQuotes(ctx context.Context, batch []models.StockQuote) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("sink panic: %v", v)
+		}
+	}()
+	return p.sink.StoreQuotes(ctx, batch)
+}
+
+// persistStatus writes a batch row when a status DB is configured.
+func (p *Pipeline) persistStatus(ctx context.Context, rec BatchRecord) {
+	p.mu.Lock()
+	db := p.statusDB
+	p.mu.Unlock()
+	if db == nil {
+		return
+	}
+	if err := RecordBatch(ctx, db, rec); err != nil {
+		log.Printf("WARNING: recording batch %s status %s: %v", rec.ID, rec.Status, err)
+	}
+}
+
+// GetBatch reports the outcome of a flushed batch, if it is still
+// within the retention window.
+func (p *Pipeline) GetBatch(id string) (BatchStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.statuses[id]
+	return status, ok
+}
+
+// StartSweeper drops expired batch statuses in the background until ctx
+// is cancelled, so a pipeline that goes idle still releases its memory.
+// Flush also sweeps inline, which keeps the map bounded either way.
+func (p *Pipeline) StartSweeper(ctx context.Context) {
+	interval := p.retention / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.sweepLocked()
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// sweepLocked drops statuses older than the retention window. Called
+// with the lock held.
+func (p *Pipeline) sweepLocked() {
+	cutoff := p.now().Add(-p.retention)
+	for id, status := range p.statuses {
+		if status.Flushed.Before(cutoff) {
+			delete(p.statuses, id)
+		}
+	}
+}
+
+// newBatchID returns a random 16-hex-digit batch identifier.
+func newBatchID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,187 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+func quote(symbol string) models.StockQuote {
+	return models.StockQuote{Symbol: symbol, Price: 100, Timestamp: time.Now()}
+}
+
+func TestPipelineFlushesAtBatchSize(t *testing.T) {
+	var stored [][]models.StockQuote
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		stored = append(stored, quotes)
+		return nil
+	}), 2)
+
+	ctx := context.Background()
+	if id, err := p.Add(ctx, quote("AAPL")); err != nil || id != "" {
+		t.Fatalf("first add: id=%q err=%v, want no flush", id, err)
+	}
+	id, err := p.Add(ctx, quote("MSFT"))
+	if err != nil {
+		t.Fatalf("second add: %v", err)
+	}
+	if id == "" {
+		t.Fatal("second add should have flushed and returned a batch ID")
+	}
+	if len(stored) != 1 || len(stored[0]) != 2 {
+		t.Fatalf("stored = %v, want one batch of two quotes", stored)
+	}
+}
+
+func TestPanickingSinkMarksBatchFailed(t *testing.T) {
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		panic("sink bug")
+	}), 1)
+
+	id, err := p.Add(context.Background(), quote("AAPL"))
+	if err == nil || !strings.Contains(err.Error(), "sink panic") {
+		t.Fatalf("err = %v, want the recovered panic as an error", err)
+	}
+	status, ok := p.GetBatch(id)
+	if !ok || status.Error == "" {
+		t.Errorf("GetBatch(%q) = %+v, %v; want the panic recorded as failure", id, status, ok)
+	}
+}
+
+func TestGetBatchReportsOutcome(t *testing.T) {
+	sinkErr := errors.New("connection reset")
+	fail := false
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		if fail {
+			return sinkErr
+		}
+		return nil
+	}), 1)
+	ctx := context.Background()
+
+	okID, err := p.Add(ctx, quote("AAPL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail = true
+	badID, err := p.Add(ctx, quote("MSFT"))
+	if err == nil {
+		t.Fatal("flush into a failing sink should return the error")
+	}
+
+	status, ok := p.GetBatch(okID)
+	if !ok || status.Quotes != 1 || status.Error != "" {
+		t.Errorf("GetBatch(%q) = %+v, %v; want 1 quote, no error", okID, status, ok)
+	}
+	status, ok = p.GetBatch(badID)
+	if !ok || status.Error != sinkErr.Error() {
+		t.Errorf("GetBatch(%q) = %+v, %v; want the sink error recorded", badID, status, ok)
+	}
+	if _, ok := p.GetBatch("missing"); ok {
+		t.Error("GetBatch should miss for an unknown ID")
+	}
+}
+
+// Sustained processing must not grow the status map without bound: the
+// inline sweep on each flush drops anything past retention.
+func TestBatchStatusesStayBounded(t *testing.T) {
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		return nil
+	}), 1)
+	p.SetRetention(10 * time.Minute)
+
+	clock := time.Now()
+	p.now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	for i := 0; i < 500; i++ {
+		if _, err := p.Add(ctx, quote("AAPL")); err != nil {
+			t.Fatal(err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+
+	p.mu.Lock()
+	size := len(p.statuses)
+	p.mu.Unlock()
+	if size > 11 { // retention window / flush interval, plus the newest
+		t.Errorf("status map has %d entries after 500 flushes, want <= 11", size)
+	}
+}
+
+func TestSweeperDropsExpiredStatuses(t *testing.T) {
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		return nil
+	}), 1)
+	p.SetRetention(time.Minute)
+
+	clock := time.Now()
+	p.now = func() time.Time { return clock }
+
+	id, err := p.Add(context.Background(), quote("AAPL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(2 * time.Minute)
+
+	p.mu.Lock()
+	p.sweepLocked()
+	p.mu.Unlock()
+	if _, ok := p.GetBatch(id); ok {
+		t.Error("expired batch status should have been swept")
+	}
+}
+
+// With a status DB configured, each flush writes the batch lifecycle to
+// data_batches: processing before the sink, then the outcome.
+func TestFlushPersistsBatchLifecycle(t *testing.T) {
+	resetMigState()
+	db, err := sql.Open("migtest", "etl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sinkErr := errors.New("disk full")
+	fail := false
+	p := NewPipeline(QuoteSinkFunc(func(ctx context.Context, quotes []models.StockQuote) error {
+		if fail {
+			return sinkErr
+		}
+		return nil
+	}), 1)
+	p.SetStatusDB(db)
+	ctx := context.Background()
+
+	if _, err := p.Add(ctx, quote("AAPL")); err != nil {
+		t.Fatal(err)
+	}
+	fail = true
+	if _, err := p.Add(ctx, quote("MSFT")); err == nil {
+		t.Fatal("flush into a failing sink should return the error")
+	}
+
+	migState.Lock()
+	defer migState.Unlock()
+	if len(migState.execs) != 4 {
+		t.Fatalf("got %d batch writes, want 4 (processing+outcome per flush)", len(migState.execs))
+	}
+	for i, exec := range migState.execs {
+		if !strings.Contains(exec, "INSERT INTO data_batches") {
+			t.Errorf("exec %d is not a batch upsert: %s", i, exec)
+		}
+	}
+	// Args are (id, status, quote_count, index_count, error).
+	wantStatus := []string{"processing", "completed", "processing", "failed"}
+	for i, want := range wantStatus {
+		if got := migState.execArgs[i][1]; got != want {
+			t.Errorf("write %d status = %v, want %s", i, got, want)
+		}
+	}
+	if got := migState.execArgs[3][4]; got != sinkErr.Error() {
+		t.Errorf("failed batch error = %v, want %q", got, sinkErr)
+	}
+}
@@ -0,0 +1,105 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Schema validates payloads against the subset of JSON Schema that the
+// models generator emits: flat object definitions with typed properties
+// and a required list. That keeps validation dependency-free while still
+// rejecting every structurally-invalid payload the scrapers can produce.
+type Schema struct {
+	Definitions map[string]*schemaDefinition `json:"definitions"`
+}
+
+type schemaDefinition struct {
+	Type       string                     `json:"type"`
+	Required   []string                   `json:"required"`
+	Properties map[string]*schemaProperty `json:"properties"`
+}
+
+type schemaProperty struct {
+	Type   string `json:"type"`
+	Format string `json:"format"`
+}
+
+// LoadSchema parses a generated finance_schema.json document.
+func LoadSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if len(s.Definitions) == 0 {
+		return nil, fmt.Errorf("schema has no definitions")
+	}
+	return &s, nil
+}
+
+// Validate checks one JSON object against the named definition,
+// returning an error naming the exact field and problem.
+func (s *Schema) Validate(defName string, raw json.RawMessage) error {
+	def, ok := s.Definitions[defName]
+	if !ok {
+		return fmt.Errorf("unknown schema definition %q", defName)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return fmt.Errorf("%s: not a JSON object: %w", defName, err)
+	}
+
+	for _, name := range def.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s.%s: required field is missing", defName, name)
+		}
+	}
+
+	for name, value := range obj {
+		prop, ok := def.Properties[name]
+		if !ok {
+			return fmt.Errorf("%s.%s: field not in schema", defName, name)
+		}
+		if err := checkType(prop, value); err != nil {
+			return fmt.Errorf("%s.%s: %w", defName, name, err)
+		}
+	}
+	return nil
+}
+
+// checkType verifies a decoded JSON value against a property type.
+func checkType(prop *schemaProperty, value interface{}) error {
+	switch prop.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+		if prop.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("expected RFC 3339 timestamp: %v", err)
+			}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("expected number, got %T", value)
+		}
+	case "integer":
+		num, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected integer, got %T", value)
+		}
+		if num != math.Trunc(num) {
+			return fmt.Errorf("expected integer, got fractional number %v", num)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected boolean, got %T", value)
+		}
+	default:
+		return fmt.Errorf("schema property has unsupported type %q", prop.Type)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+)
+
+const goodQuote = `{
+	"symbol": "AAPL",
+	"price": 190.12,
+	"change": -1.2,
+	"change_percent": -0.63,
+	"volume": 51234000,
+	"timestamp": "2025-01-02T15:04:05Z",
+	"exchange": "NASDAQ",
+	"source": "yahoo-finance"
+}`
+
+func validatingLoader(t *testing.T) *Loader {
+	t.Helper()
+	loader, err := NewLoader(true)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	return loader
+}
+
+func TestLoadStockQuotesValid(t *testing.T) {
+	loader := validatingLoader(t)
+	quotes, err := loader.LoadStockQuotes([]byte("[" + goodQuote + "," + goodQuote + "]"))
+	if err != nil {
+		t.Fatalf("LoadStockQuotes: %v", err)
+	}
+	if len(quotes) != 2 {
+		t.Fatalf("got %d quotes, want 2", len(quotes))
+	}
+	if quotes[0].Symbol != "AAPL" {
+		t.Errorf("symbol = %q, want AAPL", quotes[0].Symbol)
+	}
+}
+
+func TestLoadStockQuotesInvalid(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{
+			name:    "missing required field",
+			payload: `[{"symbol": "AAPL"}]`,
+			wantErr: "required field is missing",
+		},
+		{
+			name:    "wrong type",
+			payload: "[" + strings.Replace(goodQuote, `190.12`, `"190.12"`, 1) + "]",
+			wantErr: "expected number",
+		},
+		{
+			name:    "fractional volume",
+			payload: "[" + strings.Replace(goodQuote, `51234000`, `51234000.5`, 1) + "]",
+			wantErr: "expected integer",
+		},
+		{
+			name:    "bad timestamp",
+			payload: "[" + strings.Replace(goodQuote, `2025-01-02T15:04:05Z`, `yesterday`, 1) + "]",
+			wantErr: "RFC 3339",
+		},
+		{
+			name:    "unknown field",
+			payload: "[" + strings.Replace(goodQuote, `"symbol"`, `"sym_bogus": 1, "symbol"`, 1) + "]",
+			wantErr: "not in schema",
+		},
+		{
+			name:    "not an array",
+			payload: goodQuote,
+			wantErr: "not a JSON array",
+		},
+	}
+
+	loader := validatingLoader(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loader.LoadStockQuotes([]byte(tc.payload))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error %q does not contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoaderWithoutValidationAcceptsLoosePayloads(t *testing.T) {
+	loader, err := NewLoader(false)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	// Missing fields parse to zero values when validation is off,
+	// matching the pre-validation behavior.
+	quotes, err := loader.LoadStockQuotes([]byte(`[{"symbol": "AAPL"}]`))
+	if err != nil {
+		t.Fatalf("LoadStockQuotes: %v", err)
+	}
+	if len(quotes) != 1 || quotes[0].Price != 0 {
+		t.Fatalf("unexpected quotes: %+v", quotes)
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// modelTables maps each storage table to the model struct that is
+// written to it. The json tags on the struct double as column names.
+var modelTables = map[string]interface{}{
+	"stock_quotes":   StockQuote{},
+	"market_indices": MarketIndex{},
+}
+
+// ExpectedColumns returns the column names a model struct expects,
+// derived from its json tags.
+func ExpectedColumns(model interface{}) []string {
+	t := reflect.TypeOf(model)
+	var cols []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			cols = append(cols, name)
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// ColumnDrift describes how one table's columns diverge from its model.
+type ColumnDrift struct {
+	Table string
+	// Missing are columns the model expects but the table lacks; inserts
+	// into this table will fail until a migration adds them.
+	Missing []string
+	// Unexpected are table columns no model field maps to. Harmless for
+	// inserts, but usually a sign of a stale model.
+	Unexpected []string
+}
+
+func (d ColumnDrift) String() string {
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing columns %v", d.Missing))
+	}
+	if len(d.Unexpected) > 0 {
+		parts = append(parts, fmt.Sprintf("unexpected columns %v", d.Unexpected))
+	}
+	return fmt.Sprintf("table %s: %s", d.Table, strings.Join(parts, "; "))
+}
+
+// DiffColumns compares the columns a model expects with the columns a
+// table actually has. The id and created_at bookkeeping columns that
+// migrations add are not part of any model and are ignored.
+func DiffColumns(expected, actual []string) (missing, unexpected []string) {
+	actualSet := make(map[string]bool, len(actual))
+	for _, col := range actual {
+		actualSet[col] = true
+	}
+	expectedSet := make(map[string]bool, len(expected))
+	for _, col := range expected {
+		expectedSet[col] = true
+		if !actualSet[col] {
+			missing = append(missing, col)
+		}
+	}
+	for _, col := range actual {
+		if !expectedSet[col] && col != "id" && col != "created_at" {
+			unexpected = append(unexpected, col)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+// CheckSchemaDrift compares every model against its table's columns as
+// reported by information_schema and returns one ColumnDrift per
+// diverging table. Tables that do not exist yet are reported with all
+// columns missing.
+func CheckSchemaDrift(ctx context.Context, db *sql.DB) ([]ColumnDrift, error) {
+	var drifts []ColumnDrift
+	for table, model := range modelTables {
+		actual, err := tableColumns(ctx, db, table)
+		if err != nil {
+			return nil, fmt.Errorf("reading columns for %s: %w", table, err)
+		}
+		missing, unexpected := DiffColumns(ExpectedColumns(model), actual)
+		if len(missing) > 0 || len(unexpected) > 0 {
+			drifts = append(drifts, ColumnDrift{Table: table, Missing: missing, Unexpected: unexpected})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Table < drifts[j].Table })
+	return drifts, nil
+}
+
+func tableColumns(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, rows.Err()
+}
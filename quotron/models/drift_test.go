@@ -0,0 +1,51 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fixtureColumns mirrors what information_schema reports for a
+// stock_quotes table migrated before change_percent and the 24h range
+// columns existed.
+var fixtureColumns = []string{
+	"id", "symbol", "price", "change", "volume",
+	"timestamp", "exchange", "source", "created_at",
+}
+
+// laterColumns are the ones the fixture table predates, in model order.
+var laterColumns = []string{"change_percent", "high_24h", "low_24h"}
+
+func TestDiffColumnsMissing(t *testing.T) {
+	missing, unexpected := DiffColumns(ExpectedColumns(StockQuote{}), fixtureColumns)
+	if !reflect.DeepEqual(missing, laterColumns) {
+		t.Errorf("missing = %v, want %v", missing, laterColumns)
+	}
+	if len(unexpected) != 0 {
+		t.Errorf("unexpected = %v, want none", unexpected)
+	}
+}
+
+func TestDiffColumnsClean(t *testing.T) {
+	actual := append(append([]string{}, laterColumns...), fixtureColumns...)
+	missing, unexpected := DiffColumns(ExpectedColumns(StockQuote{}), actual)
+	if len(missing) != 0 || len(unexpected) != 0 {
+		t.Errorf("drift on matching schema: missing=%v unexpected=%v", missing, unexpected)
+	}
+}
+
+func TestDiffColumnsUnexpected(t *testing.T) {
+	actual := append(append([]string{"legacy_flag"}, laterColumns...), fixtureColumns...)
+	_, unexpected := DiffColumns(ExpectedColumns(StockQuote{}), actual)
+	if want := []string{"legacy_flag"}; !reflect.DeepEqual(unexpected, want) {
+		t.Errorf("unexpected = %v, want %v", unexpected, want)
+	}
+}
+
+func TestColumnDriftString(t *testing.T) {
+	d := ColumnDrift{Table: "stock_quotes", Missing: []string{"change_percent"}}
+	got := d.String()
+	if got != "table stock_quotes: missing columns [change_percent]" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+// Every generated enum value must round-trip through its mapping
+// function, so the constants and the alias maps cannot drift.
+func TestMapExchangeCoversAllValues(t *testing.T) {
+	for _, exchange := range AllExchanges() {
+		if got := MapExchangeToEnum(string(exchange)); got != exchange {
+			t.Errorf("MapExchangeToEnum(%q) = %q, want %q", exchange, got, exchange)
+		}
+	}
+}
+
+func TestMapSourceCoversAllValues(t *testing.T) {
+	for _, source := range AllDataSources() {
+		if got := MapSourceToEnum(string(source)); got != source {
+			t.Errorf("MapSourceToEnum(%q) = %q, want %q", source, got, source)
+		}
+	}
+}
+
+func TestMapExchangeAliases(t *testing.T) {
+	cases := map[string]Exchange{
+		"NasdaqGS":  ExchangeNASDAQ,
+		"nasdaq":    ExchangeNASDAQ,
+		" NYSE ":    ExchangeNYSE,
+		"Other OTC": ExchangeOTC,
+		"garbage":   ExchangeOther,
+	}
+	for input, want := range cases {
+		if got := MapExchangeToEnum(input); got != want {
+			t.Errorf("MapExchangeToEnum(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMapSourceUnknownFallsBackToManual(t *testing.T) {
+	if got := MapSourceToEnum("brand-new-feed"); got != SourceManual {
+		t.Errorf("MapSourceToEnum(unknown) = %q, want %q", got, SourceManual)
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "sync"
+
+// unknownExchanges counts the raw spellings NormalizeExchange could not
+// map, so the originals survive for later analysis instead of being
+// flattened into the default bucket and lost.
+var (
+	unknownExchangesMu sync.Mutex
+	unknownExchanges   = map[string]int{}
+)
+
+// NormalizeExchange maps an upstream exchange string onto the enum,
+// falling back to DefaultExchange for spellings it does not recognize.
+// Unrecognized spellings are recorded verbatim; UnknownExchanges reports
+// them, which is how new venues get noticed and added to the enum.
+func NormalizeExchange(raw string) Exchange {
+	if v, ok := MapExchangeToEnumStrict(raw); ok {
+		return v
+	}
+	unknownExchangesMu.Lock()
+	unknownExchanges[raw]++
+	unknownExchangesMu.Unlock()
+	return DefaultExchange
+}
+
+// UnknownExchanges returns a copy of the unmapped raw exchange spellings
+// seen so far, with how often each appeared.
+func UnknownExchanges() map[string]int {
+	unknownExchangesMu.Lock()
+	defer unknownExchangesMu.Unlock()
+	seen := make(map[string]int, len(unknownExchanges))
+	for raw, count := range unknownExchanges {
+		seen[raw] = count
+	}
+	return seen
+}
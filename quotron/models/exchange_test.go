@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestNormalizeExchangeKnownSpellings(t *testing.T) {
+	cases := map[string]Exchange{
+		"NasdaqGS":      ExchangeNASDAQ,
+		"NYSE American": ExchangeAMEX,
+		"ccc":           ExchangeCrypto,
+	}
+	for raw, want := range cases {
+		if got := NormalizeExchange(raw); got != want {
+			t.Errorf("NormalizeExchange(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestUnknownExchangeRetainsRawSpelling(t *testing.T) {
+	raw := "Bourse de Montréal"
+	NormalizeExchange(raw)
+	NormalizeExchange(raw)
+
+	seen := UnknownExchanges()
+	if seen[raw] != 2 {
+		t.Errorf("UnknownExchanges()[%q] = %d, want 2", raw, seen[raw])
+	}
+}
+
+func TestDefaultExchangeIsConfigurable(t *testing.T) {
+	prev := DefaultExchange
+	t.Cleanup(func() { DefaultExchange = prev })
+
+	DefaultExchange = ExchangeOTC
+	if got := NormalizeExchange("Pink Sheets"); got != ExchangeOTC {
+		t.Errorf("NormalizeExchange with overridden default = %q, want %q", got, ExchangeOTC)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+)
+
+// enumValue is one member of a generated enum: the Go constant suffix,
+// the canonical string stored in the DB, and any upstream spellings that
+// should map to it.
+type enumValue struct {
+	Name    string
+	Value   string
+	Aliases []string
+}
+
+// enumDef is the single source of truth for one DB enum. The Go
+// constants, the mapping functions, and the AllX helpers are all
+// generated from this, so they cannot drift from each other.
+type enumDef struct {
+	Type string // Go type name
+	SQL  string // DB enum name, for the doc comment
+	// Default is the constant unknown inputs map to.
+	Default string
+	Values  []enumValue
+}
+
+// enumDefs defines the exchange and data_source enums.
+var enumDefs = []enumDef{
+	{
+		Type:    "Exchange",
+		SQL:     "exchange",
+		Default: "ExchangeOther",
+		Values: []enumValue{
+			{Name: "ExchangeNYSE", Value: "NYSE", Aliases: []string{"New York Stock Exchange"}},
+			{Name: "ExchangeNASDAQ", Value: "NASDAQ", Aliases: []string{"NasdaqGS", "NasdaqCM", "NasdaqGM"}},
+			{Name: "ExchangeAMEX", Value: "AMEX", Aliases: []string{"NYSE American"}},
+			{Name: "ExchangeOTC", Value: "OTC", Aliases: []string{"Other OTC"}},
+			{Name: "ExchangeCrypto", Value: "CRYPTO", Aliases: []string{"CCC"}},
+			{Name: "ExchangeOther", Value: "OTHER"},
+		},
+	},
+	{
+		Type:    "DataSource",
+		SQL:     "data_source",
+		Default: "SourceManual",
+		Values: []enumValue{
+			{Name: "SourceYahooFinance", Value: "yahoo-finance", Aliases: []string{"yahoo"}},
+			{Name: "SourceAlphaVantage", Value: "alpha-vantage"},
+			{Name: "SourceBrowserScraper", Value: "browser-scraper"},
+			{Name: "SourceBackfill", Value: "backfill"},
+			{Name: "SourceMock", Value: "mock"},
+			{Name: "SourceManual", Value: "manual"},
+		},
+	},
+}
+
+// generateEnums writes the enums Go source file to out.
+func generateEnums(out string) error {
+	var b strings.Builder
+	b.WriteString("// Code generated by models/gen. DO NOT EDIT.\n\npackage models\n")
+
+	for _, def := range enumDefs {
+		fmt.Fprintf(&b, "\n// %s mirrors the %s enum in the DB.\ntype %s string\n\nconst (\n",
+			def.Type, def.SQL, def.Type)
+		for _, v := range def.Values {
+			fmt.Fprintf(&b, "\t%s %s = %q\n", v.Name, def.Type, v.Value)
+		}
+		b.WriteString(")\n")
+
+		// All<Type> lists every enum value, for validation and tests.
+		fmt.Fprintf(&b, "\n// All%ss lists every %s value.\nfunc All%ss() []%s {\n\treturn []%s{",
+			def.Type, def.Type, def.Type, def.Type, def.Type)
+		for i, v := range def.Values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(v.Name)
+		}
+		b.WriteString("}\n}\n")
+
+		// The alias map covers canonical values and upstream spellings,
+		// keyed lowercase.
+		fmt.Fprintf(&b, "\nvar %sAliases = map[string]%s{\n", lowerFirst(def.Type), def.Type)
+		for _, v := range def.Values {
+			fmt.Fprintf(&b, "\t%q: %s,\n", strings.ToLower(v.Value), v.Name)
+			for _, alias := range v.Aliases {
+				fmt.Fprintf(&b, "\t%q: %s,\n", strings.ToLower(alias), v.Name)
+			}
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, `
+// Default%[1]s is what Map%[1]sToEnum falls back to for values it does
+// not recognize. Services may override it at startup.
+var Default%[1]s %[2]s = %[3]s
+
+// Map%[1]sToEnum maps an upstream string to the %[2]s enum, falling back
+// to Default%[1]s for values it does not recognize.
+func Map%[1]sToEnum(s string) %[2]s {
+	if v, ok := Map%[1]sToEnumStrict(s); ok {
+		return v
+	}
+	return Default%[1]s
+}
+
+// Map%[1]sToEnumStrict is like Map%[1]sToEnum but reports whether the
+// input was actually recognized instead of silently falling back.
+func Map%[1]sToEnumStrict(s string) (%[2]s, bool) {
+	v, ok := %[4]sAliases[normalizeEnumInput(s)]
+	return v, ok
+}
+`, strings.TrimPrefix(def.Type, "Data"), def.Type, def.Default, lowerFirst(def.Type))
+	}
+
+	b.WriteString(`
+// normalizeEnumInput lowercases and trims an upstream enum string.
+func normalizeEnumInput(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+`)
+
+	src := strings.Replace(b.String(), "package models\n",
+		"package models\n\nimport \"strings\"\n", 1)
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("formatting generated enums: %w", err)
+	}
+	return os.WriteFile(out, formatted, 0o644)
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
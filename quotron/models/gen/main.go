@@ -0,0 +1,138 @@
+// gen produces finance_schema.json from the Go model structs via
+// reflection, so the ingest pipeline and Python tooling validate against
+// the exact shape the Go code expects.
+//
+// Run from the models directory:
+//
+//	go run ./gen
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// property is one field in a generated object definition.
+type property struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+}
+
+// definition is a generated JSON-schema object definition.
+type definition struct {
+	Type       string               `json:"type"`
+	Required   []string             `json:"required"`
+	Properties map[string]*property `json:"properties"`
+}
+
+// schemaDoc is the top-level finance_schema.json document.
+type schemaDoc struct {
+	Schema      string                 `json:"$schema"`
+	Title       string                 `json:"title"`
+	Definitions map[string]*definition `json:"definitions"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structDefinition reflects a model struct into a schema definition.
+// Every json-tagged field is required unless it is a pointer: the models
+// are flat snapshots and a missing field means a broken scraper, except
+// for pointer fields, which model genuinely optional data (e.g. volume).
+func structDefinition(t reflect.Type) *definition {
+	def := &definition{
+		Type:       "object",
+		Properties: map[string]*property{},
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldType := field.Type
+		optional := fieldType.Kind() == reflect.Ptr
+		if optional {
+			fieldType = fieldType.Elem()
+		}
+		def.Properties[name] = fieldProperty(fieldType)
+		if !optional {
+			def.Required = append(def.Required, name)
+		}
+	}
+	sort.Strings(def.Required)
+	return def
+}
+
+func fieldProperty(t reflect.Type) *property {
+	if t == timeType {
+		return &property{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &property{Type: "string"}
+	case reflect.Float32, reflect.Float64:
+		return &property{Type: "number"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &property{Type: "integer"}
+	case reflect.Bool:
+		return &property{Type: "boolean"}
+	default:
+		log.Fatalf("unsupported model field type %s", t)
+		return nil
+	}
+}
+
+// BuildSchema assembles the schema document for all exported models.
+func buildSchema() *schemaDoc {
+	return &schemaDoc{
+		Schema: "http://json-schema.org/draft-07/schema#",
+		Title:  "Quotron Finance Schema",
+		Definitions: map[string]*definition{
+			"stock_quote":  structDefinition(reflect.TypeOf(models.StockQuote{})),
+			"market_index": structDefinition(reflect.TypeOf(models.MarketIndex{})),
+		},
+	}
+}
+
+// generate writes the schema document to out.
+func generate(out string) error {
+	data, err := json.MarshalIndent(buildSchema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling schema: %w", err)
+	}
+	data = append(data, '\n')
+	return os.WriteFile(out, data, 0o644)
+}
+
+func main() {
+	out := flag.String("out", "finance_schema.json", "output path for the generated schema")
+	sqlOut := flag.String("sql-out", "", "also emit CREATE TABLE DDL to this path")
+	enumsOut := flag.String("enums-out", "enums.go", "output path for the generated enum source")
+	flag.Parse()
+
+	if err := generate(*out); err != nil {
+		log.Fatalf("generating schema: %v", err)
+	}
+	log.Printf("wrote %s", *out)
+
+	if err := generateEnums(*enumsOut); err != nil {
+		log.Fatalf("generating enums: %v", err)
+	}
+	log.Printf("wrote %s", *enumsOut)
+
+	if *sqlOut != "" {
+		if err := models.GenerateSQLSchema(*out, *sqlOut); err != nil {
+			log.Fatalf("generating SQL schema: %v", err)
+		}
+		log.Printf("wrote %s", *sqlOut)
+	}
+}
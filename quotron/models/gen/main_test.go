@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/we-be/tiny-ria/quotron/models"
+)
+
+// TestGeneratorRoundTrip runs the generator into a temp dir and asserts
+// the output matches the committed finance_schema.json, so a model change
+// without a regen fails CI instead of silently drifting.
+func TestGeneratorRoundTrip(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "finance_schema.json")
+	if err := generate(out); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading generated schema: %v", err)
+	}
+	if !bytes.Equal(got, models.FinanceSchemaJSON) {
+		t.Fatal("generated schema differs from committed finance_schema.json; run `go run ./gen` in models/")
+	}
+}
+
+// TestSchemaCoversModelFields asserts every json-tagged model field shows
+// up in its definition with the expected JSON type.
+func TestSchemaCoversModelFields(t *testing.T) {
+	doc := buildSchema()
+
+	cases := []struct {
+		def      string
+		model    interface{}
+		wantType map[string]string
+	}{
+		{
+			def:   "stock_quote",
+			model: models.StockQuote{},
+			wantType: map[string]string{
+				"symbol":    "string",
+				"price":     "number",
+				"volume":    "integer",
+				"timestamp": "string",
+			},
+		},
+		{
+			def:   "market_index",
+			model: models.MarketIndex{},
+			wantType: map[string]string{
+				"name":  "string",
+				"value": "number",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		def, ok := doc.Definitions[tc.def]
+		if !ok {
+			t.Fatalf("definition %q missing from schema", tc.def)
+		}
+		for _, col := range models.ExpectedColumns(tc.model) {
+			if _, ok := def.Properties[col]; !ok {
+				t.Errorf("%s: field %q missing from generated properties", tc.def, col)
+			}
+		}
+		wantRequired := 0
+		mt := reflect.TypeOf(tc.model)
+		for i := 0; i < mt.NumField(); i++ {
+			if mt.Field(i).Type.Kind() != reflect.Ptr {
+				wantRequired++
+			}
+		}
+		if len(def.Required) != wantRequired {
+			t.Errorf("%s: %d required fields, want %d; non-pointer model fields should be required",
+				tc.def, len(def.Required), wantRequired)
+		}
+		for field, want := range tc.wantType {
+			prop, ok := def.Properties[field]
+			if !ok {
+				t.Errorf("%s.%s: missing property", tc.def, field)
+				continue
+			}
+			if prop.Type != want {
+				t.Errorf("%s.%s: type = %q, want %q", tc.def, field, prop.Type, want)
+			}
+		}
+	}
+
+	if ts := doc.Definitions["stock_quote"].Properties["timestamp"]; ts.Format != "date-time" {
+		t.Errorf("stock_quote.timestamp format = %q, want date-time", ts.Format)
+	}
+
+	// Volume is a pointer field: present in properties, never required.
+	for _, field := range doc.Definitions["stock_quote"].Required {
+		if field == "volume" {
+			t.Error("stock_quote.volume is optional and should not be required")
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "strings"
+
+// indexInfo ties an upstream index symbol to its display name.
+type indexInfo struct {
+	symbol  string
+	display string
+}
+
+// indexAliases is the single alias table for market indices, so every
+// service resolves "DOW", "^DJI", and "Dow Jones" to the same thing.
+// Keys are normalized with normalizeIndexName.
+var indexAliases = map[string]indexInfo{
+	"^gspc":        {"^GSPC", "S&P 500"},
+	"s&p 500":      {"^GSPC", "S&P 500"},
+	"s&p":          {"^GSPC", "S&P 500"},
+	"sp500":        {"^GSPC", "S&P 500"},
+	"^dji":         {"^DJI", "Dow Jones"},
+	"dow jones":    {"^DJI", "Dow Jones"},
+	"dow":          {"^DJI", "Dow Jones"},
+	"djia":         {"^DJI", "Dow Jones"},
+	"^ixic":        {"^IXIC", "NASDAQ"},
+	"nasdaq":       {"^IXIC", "NASDAQ"},
+	"^rut":         {"^RUT", "Russell 2000"},
+	"russell 2000": {"^RUT", "Russell 2000"},
+	"russell":      {"^RUT", "Russell 2000"},
+}
+
+// normalizeIndexName folds case and whitespace for alias lookup.
+func normalizeIndexName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// ResolveIndex translates any alias of a market index — raw symbol,
+// short name, or display name — to its upstream symbol and display
+// name. Unknown names pass through with the name upcased as the symbol,
+// so callers degrade gracefully for indices outside the table.
+func ResolveIndex(name string) (symbol, display string) {
+	if info, ok := indexAliases[normalizeIndexName(name)]; ok {
+		return info.symbol, info.display
+	}
+	return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(name)
+}
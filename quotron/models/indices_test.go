@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestResolveIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantSymbol  string
+		wantDisplay string
+	}{
+		{"^GSPC", "^GSPC", "S&P 500"},
+		{"S&P 500", "^GSPC", "S&P 500"},
+		{"sp500", "^GSPC", "S&P 500"},
+		{"DOW", "^DJI", "Dow Jones"},
+		{"Dow Jones", "^DJI", "Dow Jones"},
+		{"djia", "^DJI", "Dow Jones"},
+		{"^dji", "^DJI", "Dow Jones"},
+		{"NASDAQ", "^IXIC", "NASDAQ"},
+		{"Russell  2000", "^RUT", "Russell 2000"},
+		{"FTSE 100", "FTSE 100", "FTSE 100"}, // unknown passes through
+	}
+	for _, tt := range tests {
+		symbol, display := ResolveIndex(tt.name)
+		if symbol != tt.wantSymbol || display != tt.wantDisplay {
+			t.Errorf("ResolveIndex(%q) = (%q, %q), want (%q, %q)",
+				tt.name, symbol, display, tt.wantSymbol, tt.wantDisplay)
+		}
+	}
+}
+
+func TestResolveIndexAliasesAgree(t *testing.T) {
+	// Every alias of one symbol must share a display name.
+	displays := map[string]string{}
+	for alias, info := range indexAliases {
+		if prev, ok := displays[info.symbol]; ok && prev != info.display {
+			t.Errorf("alias %q: display %q conflicts with %q for %s",
+				alias, info.display, prev, info.symbol)
+		}
+		displays[info.symbol] = info.display
+	}
+}
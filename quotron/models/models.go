@@ -0,0 +1,50 @@
+// Package models holds the shared data types that flow between the
+// scrapers, the ingest pipeline, storage, and the API service.
+package models
+
+import "time"
+
+// StockQuote is a single point-in-time quote for one symbol.
+type StockQuote struct {
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	// Volume is nil when the source reports no volume at all (indices,
+	// some foreign instruments); zero is a real zero-volume reading.
+	Volume *int64 `json:"volume,omitempty"`
+	// High24h and Low24h are the rolling 24-hour extremes; crypto
+	// sources report them, most equity feeds do not.
+	High24h   *float64  `json:"high_24h,omitempty"`
+	Low24h    *float64  `json:"low_24h,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Exchange  string    `json:"exchange"`
+	Source    string    `json:"source"`
+}
+
+// Int64 returns a pointer to v, for optional fields like
+// StockQuote.Volume that distinguish "unknown" from zero.
+func Int64(v int64) *int64 { return &v }
+
+// Float64 returns a pointer to v, for the optional float fields.
+func Float64(v float64) *float64 { return &v }
+
+// OHLCBucket is one aggregated interval of intraday trading for a symbol.
+type OHLCBucket struct {
+	Start  time.Time `json:"start"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume int64     `json:"volume"`
+}
+
+// MarketIndex is a point-in-time value for a market index (e.g. S&P 500).
+type MarketIndex struct {
+	Name      string    `json:"name"`
+	Symbol    string    `json:"symbol"`
+	Value     float64   `json:"value"`
+	Change    float64   `json:"change"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+}
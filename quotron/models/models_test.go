@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// An instrument with no volume data (an index, say) must serialize
+// without a volume key at all, while a real zero-volume reading keeps
+// its explicit zero.
+func TestVolumeDistinguishesUnknownFromZero(t *testing.T) {
+	unknown, err := json.Marshal(StockQuote{Symbol: "^DJI"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(unknown), "volume") {
+		t.Errorf("unknown volume should be omitted, got %s", unknown)
+	}
+
+	zero, err := json.Marshal(StockQuote{Symbol: "AAPL", Volume: Int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(zero), `"volume":0`) {
+		t.Errorf("zero volume should be explicit, got %s", zero)
+	}
+}
+
+func TestVolumeAbsentInPayloadStaysNil(t *testing.T) {
+	var q StockQuote
+	if err := json.Unmarshal([]byte(`{"symbol":"^GSPC","price":5600.5}`), &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.Volume != nil {
+		t.Errorf("Volume = %v, want nil for a payload without one", *q.Volume)
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+import _ "embed"
+
+// FinanceSchemaJSON is the generated JSON schema for the model types.
+// Regenerate with `go run ./gen` after changing a model struct.
+//
+//go:embed finance_schema.json
+var FinanceSchemaJSON []byte
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"fmt"
+	"log"
+)
+
+// StrictSources controls what happens when a quote arrives with a data
+// source the enum does not know. When false (the default) the source is
+// bucketed as SourceManual with a logged warning; when true
+// NormalizeSource returns an error so callers can reject the data.
+// Services set this from their own flags.
+var StrictSources = false
+
+// NormalizeSource maps an upstream source string to the DataSource enum.
+// Unknown sources are never silent: they either warn in the log or, in
+// strict mode, come back as an error, so a newly added data source is
+// noticed instead of quietly landing in the "manual" bucket.
+func NormalizeSource(s string) (DataSource, error) {
+	if v, ok := MapSourceToEnumStrict(s); ok {
+		return v, nil
+	}
+	if StrictSources {
+		return SourceManual, fmt.Errorf("unknown data source %q (add it to the enum definition in models/gen)", s)
+	}
+	log.Printf("WARNING: unknown data source %q, storing as %q", s, SourceManual)
+	return SourceManual, nil
+}
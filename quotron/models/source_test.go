@@ -0,0 +1,49 @@
+package models
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSourceKnown(t *testing.T) {
+	source, err := NormalizeSource("yahoo-finance")
+	if err != nil {
+		t.Fatalf("NormalizeSource: %v", err)
+	}
+	if source != SourceYahooFinance {
+		t.Errorf("source = %q, want %q", source, SourceYahooFinance)
+	}
+}
+
+func TestNormalizeSourceUnknownWarns(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	source, err := NormalizeSource("brand-new-feed")
+	if err != nil {
+		t.Fatalf("NormalizeSource: %v", err)
+	}
+	if source != SourceManual {
+		t.Errorf("source = %q, want %q", source, SourceManual)
+	}
+	if !strings.Contains(buf.String(), `unknown data source "brand-new-feed"`) {
+		t.Errorf("expected warning in log, got %q", buf.String())
+	}
+}
+
+func TestNormalizeSourceStrictRejects(t *testing.T) {
+	StrictSources = true
+	defer func() { StrictSources = false }()
+
+	_, err := NormalizeSource("brand-new-feed")
+	if err == nil {
+		t.Fatal("expected error in strict mode, got nil")
+	}
+	if !strings.Contains(err.Error(), "brand-new-feed") {
+		t.Errorf("error %q does not name the source", err)
+	}
+}
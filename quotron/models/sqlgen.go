@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// definitionTables maps schema definition names to their storage tables.
+// Kept in sync with modelTables in drift.go.
+var definitionTables = map[string]string{
+	"stock_quote":  "stock_quotes",
+	"market_index": "market_indices",
+}
+
+// sqlTypeFor maps a generated schema property to a Postgres column type.
+func sqlTypeFor(propType, format string) (string, error) {
+	switch propType {
+	case "string":
+		if format == "date-time" {
+			return "TIMESTAMPTZ", nil
+		}
+		return "TEXT", nil
+	case "number":
+		return "DOUBLE PRECISION", nil
+	case "integer":
+		return "BIGINT", nil
+	case "boolean":
+		return "BOOLEAN", nil
+	}
+	return "", fmt.Errorf("no SQL type for schema type %q", propType)
+}
+
+// GenerateSQLSchema reads a generated finance_schema.json and writes
+// CREATE TABLE DDL matching the models, so hand-written migrations can't
+// silently diverge from the structs. Required model fields become NOT
+// NULL columns, optional (pointer) fields nullable; id and created_at
+// bookkeeping columns are added per table.
+func GenerateSQLSchema(schemaPath, outPath string) error {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("reading schema: %w", err)
+	}
+
+	var doc struct {
+		Definitions map[string]struct {
+			Required   []string `json:"required"`
+			Properties map[string]struct {
+				Type   string `json:"type"`
+				Format string `json:"format"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing schema: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("-- Generated from finance_schema.json by models/gen. Do not edit.\n")
+
+	defNames := make([]string, 0, len(doc.Definitions))
+	for name := range doc.Definitions {
+		defNames = append(defNames, name)
+	}
+	sort.Strings(defNames)
+
+	for _, defName := range defNames {
+		table, ok := definitionTables[defName]
+		if !ok {
+			return fmt.Errorf("no table mapping for definition %q", defName)
+		}
+
+		props := doc.Definitions[defName].Properties
+		required := make(map[string]bool, len(doc.Definitions[defName].Required))
+		for _, col := range doc.Definitions[defName].Required {
+			required[col] = true
+		}
+		cols := make([]string, 0, len(props))
+		for name := range props {
+			cols = append(cols, name)
+		}
+		sort.Strings(cols)
+
+		fmt.Fprintf(&b, "\nCREATE TABLE IF NOT EXISTS %s (\n", table)
+		b.WriteString("    id BIGSERIAL PRIMARY KEY,\n")
+		for _, col := range cols {
+			prop := props[col]
+			sqlType, err := sqlTypeFor(prop.Type, prop.Format)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", defName, col, err)
+			}
+			notNull := ""
+			if required[col] {
+				notNull = " NOT NULL"
+			}
+			fmt.Fprintf(&b, "    %s %s%s,\n", col, sqlType, notNull)
+		}
+		b.WriteString("    created_at TIMESTAMPTZ NOT NULL DEFAULT now()\n);\n")
+	}
+
+	return os.WriteFile(outPath, []byte(b.String()), 0o644)
+}
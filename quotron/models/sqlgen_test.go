@@ -0,0 +1,56 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func generateDDL(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "finance_schema.json")
+	if err := os.WriteFile(schemaPath, FinanceSchemaJSON, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "schema.sql")
+	if err := GenerateSQLSchema(schemaPath, outPath); err != nil {
+		t.Fatalf("GenerateSQLSchema: %v", err)
+	}
+	ddl, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(ddl)
+}
+
+func TestGenerateSQLSchemaColumns(t *testing.T) {
+	ddl := generateDDL(t)
+
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS stock_quotes (",
+		"CREATE TABLE IF NOT EXISTS market_indices (",
+		"symbol TEXT NOT NULL",
+		"price DOUBLE PRECISION NOT NULL",
+		"volume BIGINT,", // nullable: unknown volume is stored as NULL
+		"timestamp TIMESTAMPTZ NOT NULL",
+		"id BIGSERIAL PRIMARY KEY",
+		"created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL missing %q:\n%s", want, ddl)
+		}
+	}
+}
+
+func TestGenerateSQLSchemaCoversModelColumns(t *testing.T) {
+	ddl := generateDDL(t)
+	for _, model := range []interface{}{StockQuote{}, MarketIndex{}} {
+		for _, col := range ExpectedColumns(model) {
+			if !strings.Contains(ddl, "\n    "+col+" ") {
+				t.Errorf("DDL missing column %q", col)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package redis is the minimal RESP2 client shared by the binaries that
+// talk to Redis streams (the agent, the ops CLI). We deliberately avoid
+// a client library: the services need a handful of stream commands, one
+// round trip each, and zero features beyond them — but the protocol
+// plumbing should exist once, not per binary.
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Conn is one Redis connection. It is not safe for concurrent use; each
+// consumer owns its connection, matching how the stream commands block.
+type Conn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// Dial connects to addr and verifies the server answers a PING.
+func Dial(addr string) (*Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{
+		conn: conn,
+		rw:   bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)),
+	}
+	if _, err := c.Do("PING"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Conn) Close() error { return c.conn.Close() }
+
+// Do sends one command and returns the parsed reply: string, int64,
+// nil, or []interface{} for arrays.
+func (c *Conn) Do(args ...string) (interface{}, error) {
+	fmt.Fprintf(c.rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.rw.Flush(); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *Conn) readReply() (interface{}, error) {
+	line, err := c.rw.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2) // include trailing \r\n
+		if _, err := io.ReadFull(c.rw.Reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if arr[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
+
+// SetReadDeadline bounds a blocking read (e.g. XREADGROUP BLOCK).
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
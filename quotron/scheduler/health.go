@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// HealthState classifies a job by its recent run outcomes.
+type HealthState string
+
+const (
+	StateHealthy  HealthState = "healthy"
+	StateDegraded HealthState = "degraded"
+	StateFailed   HealthState = "failed"
+)
+
+// HealthEvent records one job crossing a health threshold: a failure
+// degrading it, enough consecutive failures failing it, or a success
+// recovering it.
+type HealthEvent struct {
+	Job  string      `json:"job"`
+	From HealthState `json:"from"`
+	To   HealthState `json:"to"`
+	// Failures is the consecutive-failure count at the transition; zero
+	// on recovery.
+	Failures int       `json:"consecutive_failures"`
+	At       time.Time `json:"at"`
+}
+
+// HealthNotifier delivers health transitions to an external sink. A
+// failed delivery is logged, never fatal — alerting must not take the
+// jobs down with it.
+type HealthNotifier interface {
+	Notify(ctx context.Context, ev HealthEvent) error
+}
+
+// HealthPolicy tunes when transitions fire. The zero value means the
+// built-in defaults, matching the service config conventions: zero
+// fields take the default, a negative Debounce disables debouncing.
+type HealthPolicy struct {
+	// FailedAfter is how many consecutive failures turn degraded into
+	// failed; the first failure always degrades.
+	FailedAfter int
+	// Debounce suppresses a transition notification landing within this
+	// window of the previous one for the same job, so a flapping job
+	// does not page on every run.
+	Debounce time.Duration
+}
+
+const (
+	defaultFailedAfter    = 3
+	defaultHealthDebounce = time.Minute
+)
+
+func (p HealthPolicy) failedAfter() int {
+	if p.FailedAfter <= 0 {
+		return defaultFailedAfter
+	}
+	return p.FailedAfter
+}
+
+func (p HealthPolicy) debounce() time.Duration {
+	if p.Debounce == 0 {
+		return defaultHealthDebounce
+	}
+	if p.Debounce < 0 {
+		return 0
+	}
+	return p.Debounce
+}
+
+// SetHealthNotifier turns on active alerting: every run outcome updates
+// the job's health state, and transitions are delivered to the notifier.
+// Call before Start.
+func (s *Scheduler) SetHealthNotifier(n HealthNotifier, policy HealthPolicy) {
+	s.notifier = n
+	s.healthPolicy = policy
+}
+
+// classifyHealth maps a consecutive-failure count to a state.
+func classifyHealth(consecutive, failedAfter int) HealthState {
+	switch {
+	case consecutive == 0:
+		return StateHealthy
+	case consecutive < failedAfter:
+		return StateDegraded
+	default:
+		return StateFailed
+	}
+}
+
+// noteHealth folds one run outcome into the job's health state and
+// notifies on a transition. The decision is made under the job's lock;
+// the notifier runs outside it so a slow sink cannot stall bookkeeping.
+func (s *Scheduler) noteHealth(ctx context.Context, entry *jobState, failed bool) {
+	if s.notifier == nil {
+		return
+	}
+	if ctx.Err() != nil {
+		// Shutdown cancels in-flight runs; that is not a health signal.
+		return
+	}
+
+	entry.mu.Lock()
+	if failed {
+		entry.consecFails++
+	} else {
+		entry.consecFails = 0
+	}
+	prev := entry.health
+	if prev == "" {
+		prev = StateHealthy
+	}
+	next := classifyHealth(entry.consecFails, s.healthPolicy.failedAfter())
+	entry.health = next
+
+	if next == prev {
+		entry.mu.Unlock()
+		return
+	}
+	ev := HealthEvent{
+		Job:      entry.Job.Name(),
+		From:     prev,
+		To:       next,
+		Failures: entry.consecFails,
+		At:       time.Now(),
+	}
+	if window := s.healthPolicy.debounce(); window > 0 && !entry.lastNotify.IsZero() {
+		if since := time.Since(entry.lastNotify); since < window {
+			entry.mu.Unlock()
+			log.Printf("job %s health %s -> %s suppressed, last alert %v ago (debounce %v)",
+				ev.Job, ev.From, ev.To, since.Round(time.Millisecond), window)
+			return
+		}
+	}
+	entry.lastNotify = ev.At
+	entry.mu.Unlock()
+
+	log.Printf("job %s health %s -> %s after %d consecutive failure(s)", ev.Job, ev.From, ev.To, ev.Failures)
+	if err := s.notifier.Notify(ctx, ev); err != nil {
+		log.Printf("WARNING: delivering health event for %s: %v", ev.Job, err)
+	}
+}
+
+// WebhookNotifier POSTs each health event as JSON to a configured URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier builds a webhook sink with a bounded-timeout client.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, ev HealthEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", n.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// captureNotifier records every event it is handed.
+type captureNotifier struct {
+	events []HealthEvent
+}
+
+func (n *captureNotifier) Notify(ctx context.Context, ev HealthEvent) error {
+	n.events = append(n.events, ev)
+	return nil
+}
+
+// flakyJob fails while failing is true.
+type flakyJob struct {
+	name    string
+	failing *bool
+}
+
+func (j flakyJob) Name() string { return j.name }
+func (j flakyJob) Run(ctx context.Context) error {
+	if *j.failing {
+		return errors.New("upstream down")
+	}
+	return nil
+}
+
+func TestHealthTransitionsThroughDegradedToFailedAndBack(t *testing.T) {
+	failing := true
+	n := &captureNotifier{}
+
+	s := NewScheduler()
+	s.Add(flakyJob{name: "pull", failing: &failing}, time.Hour)
+	s.SetHealthNotifier(n, HealthPolicy{FailedAfter: 2, Debounce: -1})
+
+	ctx := context.Background()
+	s.RunOnce(ctx, time.Time{}, nil) // first failure: degraded
+	s.RunOnce(ctx, time.Time{}, nil) // second failure: failed
+	s.RunOnce(ctx, time.Time{}, nil) // still failed: no new event
+	failing = false
+	s.RunOnce(ctx, time.Time{}, nil) // success: recovered
+
+	want := []struct {
+		from, to HealthState
+		failures int
+	}{
+		{StateHealthy, StateDegraded, 1},
+		{StateDegraded, StateFailed, 2},
+		{StateFailed, StateHealthy, 0},
+	}
+	if len(n.events) != len(want) {
+		t.Fatalf("got %d events %+v, want %d", len(n.events), n.events, len(want))
+	}
+	for i, w := range want {
+		ev := n.events[i]
+		if ev.Job != "pull" || ev.From != w.from || ev.To != w.to || ev.Failures != w.failures {
+			t.Errorf("event %d = %+v, want %s -> %s with %d failures", i, ev, w.from, w.to, w.failures)
+		}
+	}
+}
+
+func TestHealthDebounceSuppressesFlapping(t *testing.T) {
+	failing := true
+	n := &captureNotifier{}
+
+	s := NewScheduler()
+	s.Add(flakyJob{name: "flappy", failing: &failing}, time.Hour)
+	s.SetHealthNotifier(n, HealthPolicy{FailedAfter: 2, Debounce: time.Hour})
+
+	ctx := context.Background()
+	s.RunOnce(ctx, time.Time{}, nil) // degraded: notifies
+	failing = false
+	s.RunOnce(ctx, time.Time{}, nil) // recovers inside the window: suppressed
+
+	if len(n.events) != 1 {
+		t.Fatalf("got %d events %+v, want only the first transition", len(n.events), n.events)
+	}
+	if n.events[0].To != StateDegraded {
+		t.Errorf("event = %+v, want the degrade", n.events[0])
+	}
+}
+
+func TestHealthIsPassiveWithoutNotifier(t *testing.T) {
+	failing := true
+	s := NewScheduler()
+	s.Add(flakyJob{name: "quiet", failing: &failing}, time.Hour)
+
+	// Just must not panic or track anything; the status counters still work.
+	s.RunOnce(context.Background(), time.Time{}, nil)
+	if st := s.History()[0]; st.Failures != 1 {
+		t.Errorf("failures = %d, want 1", st.Failures)
+	}
+}
+
+func TestWebhookNotifierPostsTheEvent(t *testing.T) {
+	var got HealthEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	ev := HealthEvent{Job: "pull", From: StateHealthy, To: StateFailed, Failures: 3, At: time.Now().UTC()}
+	if err := NewWebhookNotifier(srv.URL).Notify(context.Background(), ev); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.Job != "pull" || got.To != StateFailed || got.Failures != 3 {
+		t.Errorf("delivered event = %+v, want %+v", got, ev)
+	}
+}
+
+func TestWebhookNotifierSurfacesHTTPErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(srv.URL).Notify(context.Background(), HealthEvent{Job: "pull"})
+	if err == nil {
+		t.Fatal("want an error for a 502 response")
+	}
+}
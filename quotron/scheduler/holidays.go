@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// holidayDate is the date layout holiday calendars use.
+const holidayDate = "2006-01-02"
+
+// HolidayCalendar is a set of dates when equity markets are closed.
+// Equity and index jobs are skipped on those dates; crypto trades
+// through holidays and is never gated.
+type HolidayCalendar struct {
+	dates map[string]bool
+}
+
+// NewHolidayCalendar builds a calendar from YYYY-MM-DD dates.
+func NewHolidayCalendar(dates []string) (*HolidayCalendar, error) {
+	c := &HolidayCalendar{dates: make(map[string]bool, len(dates))}
+	for _, date := range dates {
+		if _, err := time.Parse(holidayDate, date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q (want YYYY-MM-DD)", date)
+		}
+		c.dates[date] = true
+	}
+	return c, nil
+}
+
+// IsHoliday reports whether t falls on a holiday. A nil calendar has no
+// holidays.
+func (c *HolidayCalendar) IsHoliday(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	return c.dates[t.Format(holidayDate)]
+}
+
+// usMarketHolidays are the NYSE/Nasdaq full closures (observed dates).
+// Extend this list as new years are published.
+var usMarketHolidays = []string{
+	// 2025
+	"2025-01-01", "2025-01-20", "2025-02-17", "2025-04-18", "2025-05-26",
+	"2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27", "2025-12-25",
+	// 2026
+	"2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25",
+	"2026-06-19", "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25",
+	// 2027
+	"2027-01-01", "2027-01-18", "2027-02-15", "2027-03-26", "2027-05-31",
+	"2027-06-18", "2027-07-05", "2027-09-06", "2027-11-25", "2027-12-24",
+}
+
+// USMarketHolidays returns the built-in US equity market holiday set.
+func USMarketHolidays() *HolidayCalendar {
+	c, err := NewHolidayCalendar(usMarketHolidays)
+	if err != nil {
+		panic(err) // the built-in list is static; a bad date is a bug
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHolidayCalendar(t *testing.T) {
+	c, err := NewHolidayCalendar([]string{"2026-12-25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsHoliday(time.Date(2026, 12, 25, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Christmas should be a holiday")
+	}
+	if c.IsHoliday(time.Date(2026, 12, 24, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Christmas Eve is not in the calendar")
+	}
+
+	var nilCal *HolidayCalendar
+	if nilCal.IsHoliday(time.Now()) {
+		t.Error("a nil calendar has no holidays")
+	}
+
+	if _, err := NewHolidayCalendar([]string{"25/12/2026"}); err == nil {
+		t.Error("malformed dates should be rejected")
+	}
+}
+
+func TestBuiltInUSCalendarKnowsChristmas(t *testing.T) {
+	c := USMarketHolidays()
+	if !c.IsHoliday(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Error("built-in calendar should include Christmas 2026")
+	}
+	if c.IsHoliday(time.Date(2026, 8, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Error("an ordinary trading day must not be a holiday")
+	}
+}
+
+func TestHolidaySkipsEquityJobsButNotCrypto(t *testing.T) {
+	today, err := NewHolidayCalendar([]string{time.Now().Format(holidayDate)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran := map[string]bool{}
+	mkJob := func(name string) funcJob {
+		return funcJob{name: name, fn: func(ctx context.Context) error {
+			ran[name] = true
+			return nil
+		}}
+	}
+
+	s := NewScheduler()
+	s.SetHolidays(today)
+	s.AddSchedule(Schedule{Job: mkJob("equities"), Every: time.Hour, SkipOnHolidays: true})
+	s.AddSchedule(Schedule{Job: mkJob("coins"), Every: time.Hour})
+
+	s.RunOnce(context.Background(), time.Time{}, nil)
+
+	if ran["equities"] {
+		t.Error("equity job ran on a market holiday")
+	}
+	if !ran["coins"] {
+		t.Error("crypto job should run through holidays")
+	}
+}
+
+func TestBuildJobsSetsHolidayGatePerType(t *testing.T) {
+	schedules, err := BuildJobs([]JobConfig{
+		{Name: "tech", Type: JobStockQuotes, Every: "5m", Symbols: []string{"AAPL"}},
+		{Name: "indices", Type: JobMarketIndices, Every: "5m"},
+		{Name: "coins", Type: JobCryptoQuotes, Every: "5m", Symbols: []string{"BTC"}},
+	}, Deps{APIBaseURL: "http://api:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schedules[0].SkipOnHolidays || !schedules[1].SkipOnHolidays {
+		t.Error("equity and index jobs should observe market holidays")
+	}
+	if schedules[2].SkipOnHolidays {
+		t.Error("crypto jobs must not observe market holidays")
+	}
+}
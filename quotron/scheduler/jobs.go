@@ -0,0 +1,282 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job type names accepted in the config file.
+const (
+	JobStockQuotes   = "stock_quotes"
+	JobMarketIndices = "market_indices"
+	JobCryptoQuotes  = "crypto_quotes"
+	JobHTTPFetch     = "http_fetch"
+)
+
+// JobConfig is one declarative job entry. Every job has a name, a type,
+// and an interval; the remaining fields depend on the type:
+//
+//   - stock_quotes and crypto_quotes need Symbols
+//   - market_indices pulls the API service's configured index set
+//   - http_fetch needs URL and Stream
+type JobConfig struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Every   string   `json:"every"`
+	Symbols []string `json:"symbols,omitempty"`
+	URL     string   `json:"url,omitempty"`
+	Stream  string   `json:"stream,omitempty"`
+	// Jitter delays each run by a random amount up to this duration,
+	// spreading jobs that share an interval. Empty means no jitter.
+	Jitter string `json:"jitter,omitempty"`
+	// Timeout cancels a run that exceeds this duration, so a stuck
+	// upstream cannot block the job forever. Empty means no limit.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Deps carries what the job types need to run: the API service the
+// quote jobs pull through, and the publisher http_fetch results go to.
+type Deps struct {
+	// APIBaseURL is the API service the quote, index, and crypto jobs
+	// call; fetched data is stored by the service's write-back path.
+	APIBaseURL string
+	// HTTPClient overrides the client jobs use; nil means a 30s-timeout
+	// default.
+	HTTPClient *http.Client
+	// Publish delivers an http_fetch result to a named stream. Required
+	// only when an http_fetch job is configured.
+	Publish func(ctx context.Context, stream string, payload []byte) error
+}
+
+func (d Deps) client() *http.Client {
+	if d.HTTPClient != nil {
+		return d.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// LoadJobConfigs reads the config file: a JSON array of JobConfig.
+func LoadJobConfigs(path string) ([]JobConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []JobConfig
+	if err := json.Unmarshal(data, &cfgs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfgs, nil
+}
+
+// BuildJobs turns the config entries into runnable schedules, validating
+// as it goes so a bad config fails at startup, not at first fire.
+func BuildJobs(cfgs []JobConfig, deps Deps) ([]Schedule, error) {
+	schedules := make([]Schedule, 0, len(cfgs))
+	seen := map[string]bool{}
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("job with type %q has no name", cfg.Type)
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("duplicate job name %q", cfg.Name)
+		}
+		seen[cfg.Name] = true
+
+		every, err := time.ParseDuration(cfg.Every)
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("job %s: invalid interval %q", cfg.Name, cfg.Every)
+		}
+
+		var jitter time.Duration
+		if cfg.Jitter != "" {
+			jitter, err = time.ParseDuration(cfg.Jitter)
+			if err != nil || jitter < 0 {
+				return nil, fmt.Errorf("job %s: invalid jitter %q", cfg.Name, cfg.Jitter)
+			}
+		}
+		var timeout time.Duration
+		if cfg.Timeout != "" {
+			timeout, err = time.ParseDuration(cfg.Timeout)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("job %s: invalid timeout %q", cfg.Name, cfg.Timeout)
+			}
+		}
+
+		job, err := buildJob(cfg, deps)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, Schedule{
+			Job:     job,
+			Every:   every,
+			Jitter:  jitter,
+			Timeout: timeout,
+			// Crypto trades through market holidays; only equity-market
+			// jobs observe the calendar.
+			SkipOnHolidays: cfg.Type == JobStockQuotes || cfg.Type == JobMarketIndices,
+		})
+	}
+	return schedules, nil
+}
+
+// JobBuilder constructs a job of one type from its config entry,
+// validating the type-specific fields.
+type JobBuilder func(cfg JobConfig, deps Deps) (Job, error)
+
+var (
+	jobTypesMu sync.RWMutex
+	jobTypes   = map[string]JobBuilder{}
+)
+
+// RegisterJobType makes a job type constructible from config, so custom
+// fetch jobs plug in without touching core code. It panics on a
+// duplicate or empty name, mirroring client.RegisterClient: both are
+// programmer errors best caught at init time.
+func RegisterJobType(name string, build JobBuilder) {
+	jobTypesMu.Lock()
+	defer jobTypesMu.Unlock()
+	if name == "" || build == nil {
+		panic("scheduler: RegisterJobType needs a name and a builder")
+	}
+	if _, dup := jobTypes[name]; dup {
+		panic("scheduler: RegisterJobType called twice for " + name)
+	}
+	jobTypes[name] = build
+}
+
+// buildJob constructs one job from its config entry via the registry.
+func buildJob(cfg JobConfig, deps Deps) (Job, error) {
+	jobTypesMu.RLock()
+	build, ok := jobTypes[cfg.Type]
+	jobTypesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("job %s: unknown job type %q (registered: %s)",
+			cfg.Name, cfg.Type, strings.Join(registeredJobTypes(), ", "))
+	}
+	return build(cfg, deps)
+}
+
+func registeredJobTypes() []string {
+	jobTypesMu.RLock()
+	defer jobTypesMu.RUnlock()
+	names := make([]string, 0, len(jobTypes))
+	for name := range jobTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterJobType(JobStockQuotes, func(cfg JobConfig, deps Deps) (Job, error) {
+		if len(cfg.Symbols) == 0 {
+			return nil, fmt.Errorf("job %s: stock_quotes needs symbols", cfg.Name)
+		}
+		return &apiJob{
+			name:   cfg.Name,
+			url:    deps.APIBaseURL + "/api/quotes/batch?store=true&symbols=" + url.QueryEscape(strings.Join(cfg.Symbols, ",")),
+			client: deps.client(),
+		}, nil
+	})
+	RegisterJobType(JobCryptoQuotes, func(cfg JobConfig, deps Deps) (Job, error) {
+		if len(cfg.Symbols) == 0 {
+			return nil, fmt.Errorf("job %s: crypto_quotes needs symbols", cfg.Name)
+		}
+		return &apiJob{
+			name:   cfg.Name,
+			url:    deps.APIBaseURL + "/api/crypto/batch?store=true&symbols=" + url.QueryEscape(strings.Join(cfg.Symbols, ",")),
+			client: deps.client(),
+		}, nil
+	})
+	RegisterJobType(JobMarketIndices, func(cfg JobConfig, deps Deps) (Job, error) {
+		return &apiJob{
+			name:   cfg.Name,
+			url:    deps.APIBaseURL + "/api/indices?store=true",
+			client: deps.client(),
+		}, nil
+	})
+	RegisterJobType(JobHTTPFetch, func(cfg JobConfig, deps Deps) (Job, error) {
+		if cfg.URL == "" || cfg.Stream == "" {
+			return nil, fmt.Errorf("job %s: http_fetch needs url and stream", cfg.Name)
+		}
+		if deps.Publish == nil {
+			return nil, fmt.Errorf("job %s: http_fetch configured but no publisher available", cfg.Name)
+		}
+		return &httpFetchJob{
+			name:    cfg.Name,
+			url:     cfg.URL,
+			stream:  cfg.Stream,
+			client:  deps.client(),
+			publish: deps.Publish,
+		}, nil
+	})
+}
+
+// apiJob GETs one API-service endpoint; the service's own write-back
+// stores whatever the fetch produced. Partial batch failures (207) are
+// the service's business, not a job failure.
+type apiJob struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func (j *apiJob) Name() string { return j.name }
+
+func (j *apiJob) Run(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusMultiStatus {
+		return fmt.Errorf("GET %s: %s", j.url, resp.Status)
+	}
+	return nil
+}
+
+// httpFetchJob GETs an arbitrary URL and publishes the body to a named
+// stream, for sources that have no first-class job type.
+type httpFetchJob struct {
+	name    string
+	url     string
+	stream  string
+	client  *http.Client
+	publish func(ctx context.Context, stream string, payload []byte) error
+}
+
+func (j *httpFetchJob) Name() string { return j.name }
+
+func (j *httpFetchJob) Run(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", j.url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return j.publish(ctx, j.stream, body)
+}
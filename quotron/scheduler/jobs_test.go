@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildJobsFromConfig(t *testing.T) {
+	cfgs := []JobConfig{
+		{Name: "tech", Type: JobStockQuotes, Every: "5m", Symbols: []string{"AAPL", "MSFT"}},
+		{Name: "coins", Type: JobCryptoQuotes, Every: "1m", Symbols: []string{"BTC"}, Jitter: "30s"},
+		{Name: "indices", Type: JobMarketIndices, Every: "10m", Timeout: "45s"},
+	}
+	schedules, err := BuildJobs(cfgs, Deps{APIBaseURL: "http://api:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedules) != 3 {
+		t.Fatalf("got %d schedules, want 3", len(schedules))
+	}
+	if schedules[0].Job.Name() != "tech" {
+		t.Errorf("job name = %q, want tech", schedules[0].Job.Name())
+	}
+	if got := schedules[0].Job.(*apiJob).url; !strings.Contains(got, "/api/quotes/batch") || !strings.Contains(got, "AAPL%2CMSFT") {
+		t.Errorf("stock job URL = %q, want the batch endpoint with both symbols", got)
+	}
+	if got := schedules[1].Job.(*apiJob).url; !strings.Contains(got, "/api/crypto/batch") {
+		t.Errorf("crypto job URL = %q, want the crypto batch endpoint", got)
+	}
+	if schedules[1].Jitter != 30*time.Second {
+		t.Errorf("jitter = %v, want 30s", schedules[1].Jitter)
+	}
+	if schedules[2].Timeout != 45*time.Second {
+		t.Errorf("timeout = %v, want 45s", schedules[2].Timeout)
+	}
+}
+
+func TestBuildJobsValidation(t *testing.T) {
+	publish := func(ctx context.Context, stream string, payload []byte) error { return nil }
+	cases := []struct {
+		cfg  JobConfig
+		want string
+	}{
+		{JobConfig{Name: "x", Type: "ftp_fetch", Every: "1m"}, `unknown job type "ftp_fetch"`},
+		{JobConfig{Type: JobStockQuotes, Every: "1m"}, "has no name"},
+		{JobConfig{Name: "x", Type: JobStockQuotes, Every: "soon", Symbols: []string{"A"}}, `invalid interval "soon"`},
+		{JobConfig{Name: "x", Type: JobStockQuotes, Every: "1m"}, "needs symbols"},
+		{JobConfig{Name: "x", Type: JobHTTPFetch, Every: "1m", Stream: "s"}, "needs url and stream"},
+		{JobConfig{Name: "x", Type: JobMarketIndices, Every: "1m", Jitter: "lots"}, `invalid jitter "lots"`},
+		{JobConfig{Name: "x", Type: JobMarketIndices, Every: "1m", Timeout: "-5s"}, `invalid timeout "-5s"`},
+	}
+	for _, c := range cases {
+		_, err := BuildJobs([]JobConfig{c.cfg}, Deps{Publish: publish})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("BuildJobs(%+v) err = %v, want it to mention %q", c.cfg, err, c.want)
+		}
+	}
+
+	dup := []JobConfig{
+		{Name: "x", Type: JobMarketIndices, Every: "1m"},
+		{Name: "x", Type: JobMarketIndices, Every: "1m"},
+	}
+	if _, err := BuildJobs(dup, Deps{}); err == nil || !strings.Contains(err.Error(), "duplicate job name") {
+		t.Errorf("duplicate names should be rejected, got %v", err)
+	}
+}
+
+func TestRegisteredJobTypeBuildsFromConfig(t *testing.T) {
+	type noopJob struct{ funcJob }
+	RegisterJobType("test_noop", func(cfg JobConfig, deps Deps) (Job, error) {
+		return &noopJob{funcJob{name: cfg.Name, fn: func(ctx context.Context) error { return nil }}}, nil
+	})
+
+	schedules, err := BuildJobs([]JobConfig{
+		{Name: "custom", Type: "test_noop", Every: "1m"},
+	}, Deps{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schedules[0].Job.(*noopJob); !ok {
+		t.Errorf("job = %T, want the registered custom type", schedules[0].Job)
+	}
+}
+
+func TestRegisterJobTypeRejectsDuplicates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration should panic")
+		}
+	}()
+	RegisterJobType(JobStockQuotes, func(cfg JobConfig, deps Deps) (Job, error) { return nil, nil })
+}
+
+func TestHTTPFetchJobPublishesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rate": 1.07}`))
+	}))
+	defer srv.Close()
+
+	var gotStream, gotBody string
+	publish := func(ctx context.Context, stream string, payload []byte) error {
+		gotStream, gotBody = stream, string(payload)
+		return nil
+	}
+	schedules, err := BuildJobs([]JobConfig{
+		{Name: "fx", Type: JobHTTPFetch, Every: "1h", URL: srv.URL, Stream: "quotron:fx"},
+	}, Deps{Publish: publish})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schedules[0].Job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if gotStream != "quotron:fx" || gotBody != `{"rate": 1.07}` {
+		t.Errorf("published (%q, %q), want the body on quotron:fx", gotStream, gotBody)
+	}
+}
+
+func TestAPIJobTreatsPartialBatchAsSuccess(t *testing.T) {
+	status := http.StatusMultiStatus
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	schedules, err := BuildJobs([]JobConfig{
+		{Name: "tech", Type: JobStockQuotes, Every: "5m", Symbols: []string{"AAPL"}},
+	}, Deps{APIBaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schedules[0].Job.Run(context.Background()); err != nil {
+		t.Errorf("207 should not fail the job: %v", err)
+	}
+	status = http.StatusBadGateway
+	if err := schedules[0].Job.Run(context.Background()); err == nil {
+		t.Error("502 should fail the job")
+	}
+}
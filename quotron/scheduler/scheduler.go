@@ -0,0 +1,286 @@
+// Package scheduler runs the recurring data-collection jobs: periodic
+// quote and index pulls through the API service, plus operator-defined
+// HTTP fetches. Jobs are declared in a JSON config file (see BuildJobs)
+// rather than in code, so adding a second stock-quotes job with its own
+// symbol set is a config edit, not a release.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Job is one unit of recurring work.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Schedule pairs a job with when it runs. A non-zero Jitter delays each
+// run by a random amount up to that value, so jobs sharing an interval
+// do not slam the upstream in the same instant. A non-zero Timeout
+// cancels a run's context after that long, so a stuck run cannot block
+// the job forever.
+type Schedule struct {
+	Job     Job
+	Every   time.Duration
+	Jitter  time.Duration
+	Timeout time.Duration
+	// SkipOnHolidays gates the job on the scheduler's holiday calendar:
+	// equity and index jobs set it, crypto and plain HTTP fetches do not.
+	SkipOnHolidays bool
+}
+
+// FireTimes projects when this schedule would run, starting at from:
+// Start fires a job immediately and then every interval, so the first
+// entry is from itself. Projection stops at the end of the window or
+// after max entries, whichever comes first. Jitter is not included —
+// it shifts each real run by at most Schedule.Jitter.
+func (sch Schedule) FireTimes(from time.Time, window time.Duration, max int) []time.Time {
+	var times []time.Time
+	end := from.Add(window)
+	for at := from; !at.After(end) && len(times) < max; at = at.Add(sch.Every) {
+		times = append(times, at)
+	}
+	return times
+}
+
+// JobStatus is a snapshot of one job's run history.
+type JobStatus struct {
+	Name     string
+	Runs     int
+	Failures int
+	// Skips counts triggers dropped because the previous run was still
+	// in progress.
+	Skips   int
+	LastRun time.Time
+}
+
+// jobState is a Schedule plus its run bookkeeping.
+type jobState struct {
+	Schedule
+
+	mu      sync.Mutex
+	running bool
+	status  JobStatus
+
+	// Health alerting state; only maintained when a notifier is set.
+	consecFails int
+	health      HealthState
+	lastNotify  time.Time
+}
+
+// Scheduler runs each added job on its own interval until the context
+// ends. Failures are logged, never fatal: one broken upstream must not
+// stop the other jobs.
+type Scheduler struct {
+	jobs []*jobState
+
+	// holidays gates jobs with SkipOnHolidays; nil means no gating.
+	holidays *HolidayCalendar
+
+	// jitter picks the actual delay for one run; swapped in tests.
+	jitter func(max time.Duration) time.Duration
+
+	// notifier, when set, receives health state transitions per
+	// healthPolicy; nil keeps health reporting passive.
+	notifier     HealthNotifier
+	healthPolicy HealthPolicy
+}
+
+// NewScheduler builds an empty scheduler; add jobs before Start.
+func NewScheduler() *Scheduler {
+	return &Scheduler{jitter: randomDelay}
+}
+
+// Add schedules a job to run every interval, with no jitter.
+func (s *Scheduler) Add(job Job, every time.Duration) {
+	s.AddSchedule(Schedule{Job: job, Every: every})
+}
+
+// AddSchedule adds a job with its full schedule, jitter included.
+func (s *Scheduler) AddSchedule(sch Schedule) {
+	s.jobs = append(s.jobs, &jobState{
+		Schedule: sch,
+		status:   JobStatus{Name: sch.Job.Name()},
+	})
+}
+
+// SetHolidays installs the holiday calendar consulted before each run
+// of a job with SkipOnHolidays. Call before Start.
+func (s *Scheduler) SetHolidays(c *HolidayCalendar) {
+	s.holidays = c
+}
+
+// holidayGated reports whether this trigger falls on a market holiday
+// for a job that observes them.
+func (s *Scheduler) holidayGated(entry *jobState) bool {
+	return entry.SkipOnHolidays && s.holidays.IsHoliday(time.Now())
+}
+
+// History snapshots every job's run counts, in the order jobs were added.
+func (s *Scheduler) History() []JobStatus {
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, entry := range s.jobs {
+		entry.mu.Lock()
+		out = append(out, entry.status)
+		entry.mu.Unlock()
+	}
+	return out
+}
+
+// randomDelay picks a uniform delay in [0, max).
+func randomDelay(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// Start launches one goroutine per job and blocks until ctx ends and
+// every in-flight run has returned. Each job runs once immediately, so
+// a fresh deployment has data before the first interval elapses.
+//
+// Each trigger runs in its own goroutine so a slow run cannot stall the
+// ticker; the overlap guard in tryRun skips triggers that land while the
+// previous run is still going.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, entry := range s.jobs {
+		wg.Add(1)
+		go func(entry *jobState) {
+			defer wg.Done()
+			ticker := time.NewTicker(entry.Every)
+			defer ticker.Stop()
+			s.tryRun(ctx, &wg, entry)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.tryRun(ctx, &wg, entry)
+				}
+			}
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// RunOnce runs every job once, in the order added, then returns. Jobs
+// whose recorded last success is after since are skipped — that is the
+// catch-up mode after downtime: pass the jobs' persisted success times
+// and only the stale ones run. A zero since runs everything. The return
+// value maps each job that succeeded this run to its completion time,
+// for the caller to persist.
+func (s *Scheduler) RunOnce(ctx context.Context, since time.Time, lastSuccess map[string]time.Time) map[string]time.Time {
+	succeeded := map[string]time.Time{}
+	for _, entry := range s.jobs {
+		name := entry.Job.Name()
+		if s.holidayGated(entry) {
+			log.Printf("skipping %s — market holiday", name)
+			continue
+		}
+		if !since.IsZero() {
+			if at, ok := lastSuccess[name]; ok && at.After(since) {
+				log.Printf("job %s skipped, already succeeded at %s", name, at.Format(time.RFC3339))
+				continue
+			}
+		}
+		if !s.runTracked(ctx, entry) {
+			succeeded[name] = time.Now()
+		}
+	}
+	return succeeded
+}
+
+// tryRun starts one run of the job unless the previous run is still in
+// progress, in which case the trigger is skipped and counted.
+func (s *Scheduler) tryRun(ctx context.Context, wg *sync.WaitGroup, entry *jobState) {
+	if s.holidayGated(entry) {
+		log.Printf("skipping %s — market holiday", entry.Job.Name())
+		return
+	}
+	entry.mu.Lock()
+	if entry.running {
+		entry.status.Skips++
+		entry.mu.Unlock()
+		log.Printf("job %s skipped, previous run still in progress", entry.Job.Name())
+		return
+	}
+	entry.running = true
+	entry.mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			entry.mu.Lock()
+			entry.running = false
+			entry.mu.Unlock()
+		}()
+		s.runDelayed(ctx, entry)
+	}()
+}
+
+// runDelayed waits out this run's jitter, then runs the job. The log
+// line at the end of the wait is the job's actual start time.
+func (s *Scheduler) runDelayed(ctx context.Context, entry *jobState) {
+	if delay := s.jitter(entry.Jitter); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		log.Printf("job %s starting after %v jitter delay", entry.Job.Name(), delay.Round(time.Millisecond))
+	}
+	s.runTracked(ctx, entry)
+}
+
+// runTracked runs the job once under its timeout, records the outcome
+// in the job's history, and reports whether the run failed.
+func (s *Scheduler) runTracked(ctx context.Context, entry *jobState) (failed bool) {
+	runCtx := ctx
+	if entry.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, entry.Timeout)
+		defer cancel()
+	}
+	failed = runJob(runCtx, entry.Job)
+
+	entry.mu.Lock()
+	entry.status.Runs++
+	entry.status.LastRun = time.Now()
+	if failed {
+		entry.status.Failures++
+	}
+	entry.mu.Unlock()
+
+	s.noteHealth(ctx, entry, failed)
+	return failed
+}
+
+// runJob executes one run under a panic guard, logging the outcome, and
+// reports whether the run failed.
+func runJob(ctx context.Context, job Job) (failed bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			failed = true
+			log.Printf("ERROR: panic in job %s: %v\n%s", job.Name(), v, debug.Stack())
+		}
+	}()
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		if ctx.Err() == context.Canceled {
+			// Shutdown, not a job problem; stay quiet.
+			return true
+		}
+		log.Printf("WARNING: job %s failed after %v: %v", job.Name(), time.Since(start).Round(time.Millisecond), err)
+		return true
+	}
+	log.Printf("job %s completed in %v", job.Name(), time.Since(start).Round(time.Millisecond))
+	return false
+}
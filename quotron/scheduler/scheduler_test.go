@@ -0,0 +1,306 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// funcJob adapts a function to the Job interface for tests.
+type funcJob struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (j funcJob) Name() string                  { return j.name }
+func (j funcJob) Run(ctx context.Context) error { return j.fn(ctx) }
+
+// waitForStatus polls the scheduler's history until ok accepts the
+// first job's status, failing the test after two seconds.
+func waitForStatus(t *testing.T, s *Scheduler, ok func(JobStatus) bool) JobStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if st := s.History()[0]; ok(st) {
+			return st
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("status never reached the expected state: %+v", s.History()[0])
+	return JobStatus{}
+}
+
+func TestOverlappingTriggersAreSkipped(t *testing.T) {
+	block := make(chan struct{})
+	var starts atomic.Int32
+	job := funcJob{name: "slow", fn: func(ctx context.Context) error {
+		starts.Add(1)
+		<-block
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.AddSchedule(Schedule{Job: job, Every: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+
+	st := waitForStatus(t, s, func(st JobStatus) bool { return st.Skips >= 2 })
+	if got := starts.Load(); got != 1 {
+		t.Errorf("job started %d times while the first run was blocked, want 1", got)
+	}
+	if st.Runs != 0 {
+		t.Errorf("runs = %d before the blocked run finished, want 0", st.Runs)
+	}
+
+	close(block)
+	cancel()
+	<-done
+
+	if st := s.History()[0]; st.Runs < 1 {
+		t.Errorf("runs = %d after release, want at least 1", st.Runs)
+	}
+}
+
+func TestRunTimeoutCancelsStuckRun(t *testing.T) {
+	errCh := make(chan error, 1)
+	job := funcJob{name: "stuck", fn: func(ctx context.Context) error {
+		<-ctx.Done()
+		errCh <- ctx.Err()
+		return ctx.Err()
+	}}
+
+	s := NewScheduler()
+	s.AddSchedule(Schedule{Job: job, Every: time.Hour, Timeout: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("run context ended with %v, want a deadline error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stuck run was never canceled")
+	}
+	waitForStatus(t, s, func(st JobStatus) bool { return st.Failures == 1 })
+}
+
+// Start and RunOnce track completion (WaitGroup and synchronous runs
+// respectively) rather than sleeping for a guessed duration, so the
+// process exits as soon as the last job finishes. These tests pin that
+// down.
+
+func TestStartReturnsAsSoonAsInFlightRunsFinish(t *testing.T) {
+	release := make(chan struct{})
+	job := funcJob{name: "slow", fn: func(ctx context.Context) error {
+		<-release
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.Add(job, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Start returned while a run was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	freed := time.Now()
+	select {
+	case <-done:
+		if waited := time.Since(freed); waited > time.Second {
+			t.Errorf("Start took %v to return after the last run finished", waited)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start never returned after the run completed")
+	}
+}
+
+func TestRunOnceReturnsWhenJobsComplete(t *testing.T) {
+	s := NewScheduler()
+	for _, name := range []string{"a", "b", "c"} {
+		s.Add(funcJob{name: name, fn: func(ctx context.Context) error { return nil }}, time.Hour)
+	}
+
+	start := time.Now()
+	s.RunOnce(context.Background(), time.Time{}, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RunOnce took %v for instant jobs; it must not sleep a fixed interval", elapsed)
+	}
+}
+
+func TestFireTimesProjectTheWindow(t *testing.T) {
+	sch := Schedule{Job: funcJob{name: "x"}, Every: 6 * time.Hour}
+	from := time.Date(2026, 8, 27, 9, 0, 0, 0, time.UTC)
+
+	fires := sch.FireTimes(from, 24*time.Hour, 100)
+	if len(fires) != 5 {
+		t.Fatalf("got %d fire times, want 5 (immediate + four intervals)", len(fires))
+	}
+	if !fires[0].Equal(from) {
+		t.Errorf("first fire = %v, want the immediate run at %v", fires[0], from)
+	}
+	if want := from.Add(24 * time.Hour); !fires[4].Equal(want) {
+		t.Errorf("last fire = %v, want %v", fires[4], want)
+	}
+
+	if capped := sch.FireTimes(from, 24*time.Hour, 3); len(capped) != 3 {
+		t.Errorf("got %d fire times with max 3, want 3", len(capped))
+	}
+}
+
+func TestRunOnceSkipsRecentSuccesses(t *testing.T) {
+	ran := map[string]bool{}
+	mkJob := func(name string) funcJob {
+		return funcJob{name: name, fn: func(ctx context.Context) error {
+			ran[name] = true
+			return nil
+		}}
+	}
+
+	s := NewScheduler()
+	s.Add(mkJob("fresh"), time.Hour)
+	s.Add(mkJob("stale"), time.Hour)
+	s.Add(mkJob("unknown"), time.Hour)
+
+	now := time.Now()
+	since := now.Add(-time.Hour)
+	lastSuccess := map[string]time.Time{
+		"fresh": now.Add(-time.Minute),   // succeeded after since: skip
+		"stale": now.Add(-2 * time.Hour), // succeeded before since: run
+	}
+	succeeded := s.RunOnce(context.Background(), since, lastSuccess)
+
+	if ran["fresh"] {
+		t.Error("fresh job ran despite succeeding after --since")
+	}
+	if !ran["stale"] || !ran["unknown"] {
+		t.Errorf("ran = %v, want the stale and unknown jobs to run", ran)
+	}
+	if _, ok := succeeded["fresh"]; ok {
+		t.Error("skipped job must not get a new success time")
+	}
+	if _, ok := succeeded["stale"]; !ok {
+		t.Error("successful run should report a success time")
+	}
+}
+
+func TestRunOnceWithZeroSinceRunsEverything(t *testing.T) {
+	runs := 0
+	job := funcJob{name: "always", fn: func(ctx context.Context) error {
+		runs++
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.Add(job, time.Hour)
+	s.RunOnce(context.Background(), time.Time{}, map[string]time.Time{
+		"always": time.Now(),
+	})
+
+	if runs != 1 {
+		t.Errorf("runs = %d, want 1 regardless of last success", runs)
+	}
+}
+
+func TestJitterDelaysTheRun(t *testing.T) {
+	ran := make(chan time.Time, 1)
+	job := funcJob{name: "jittered", fn: func(ctx context.Context) error {
+		ran <- time.Now()
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.jitter = func(max time.Duration) time.Duration {
+		if max != time.Hour {
+			t.Errorf("jitter max = %v, want the schedule's 1h", max)
+		}
+		return 30 * time.Millisecond
+	}
+	s.AddSchedule(Schedule{Job: job, Every: time.Hour, Jitter: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	go s.Start(ctx)
+	defer cancel()
+
+	select {
+	case at := <-ran:
+		if delay := at.Sub(start); delay < 30*time.Millisecond {
+			t.Errorf("job ran after %v, want at least the 30ms jitter delay", delay)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("job never ran")
+	}
+}
+
+func TestZeroJitterRunsImmediately(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	job := funcJob{name: "plain", fn: func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.Add(job, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx)
+	defer cancel()
+
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job without jitter should run right away")
+	}
+}
+
+func TestCancelDuringJitterSkipsTheRun(t *testing.T) {
+	ran := make(chan struct{}, 1)
+	job := funcJob{name: "never", fn: func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}}
+
+	s := NewScheduler()
+	s.jitter = func(time.Duration) time.Duration { return time.Hour }
+	s.AddSchedule(Schedule{Job: job, Every: time.Hour, Jitter: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start did not return after cancellation")
+	}
+	select {
+	case <-ran:
+		t.Error("job ran despite cancellation during its jitter wait")
+	default:
+	}
+}
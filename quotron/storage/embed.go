@@ -0,0 +1,11 @@
+// Package storage ships the SQL migrations inside the binaries that run
+// them, so the migration runner never has to locate files on disk.
+package storage
+
+import "embed"
+
+// Migrations holds every top-level migration file, forward and down.
+// The optional/ migrations are excluded: those are applied by hand.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,26 @@
+-- Initial schema for quote storage. Keep in sync with the models; the
+-- DDL shape matches what models/gen -sql-out emits.
+
+CREATE TABLE IF NOT EXISTS stock_quotes (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    change DOUBLE PRECISION NOT NULL,
+    change_percent DOUBLE PRECISION NOT NULL,
+    volume BIGINT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    exchange TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS market_indices (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    change DOUBLE PRECISION NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+-- Undo 001: drop the core tables (and everything in them).
+
+DROP TABLE IF EXISTS market_indices;
+DROP TABLE IF EXISTS stock_quotes;
@@ -0,0 +1,9 @@
+-- The history and latest-quote queries filter on symbol and order by
+-- timestamp DESC; composite indexes let Postgres serve both with a
+-- single backward index scan instead of sorting.
+
+CREATE INDEX IF NOT EXISTS idx_stock_quotes_symbol_timestamp
+    ON stock_quotes (symbol, timestamp DESC);
+
+CREATE INDEX IF NOT EXISTS idx_market_indices_symbol_timestamp
+    ON market_indices (symbol, timestamp DESC);
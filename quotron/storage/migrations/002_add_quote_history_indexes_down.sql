@@ -0,0 +1,4 @@
+-- Undo 002: drop the history composite indexes.
+
+DROP INDEX IF EXISTS idx_stock_quotes_symbol_timestamp;
+DROP INDEX IF EXISTS idx_market_indices_symbol_timestamp;
@@ -0,0 +1,5 @@
+-- Uniqueness target for the insert conflict policies: at most one row
+-- per symbol per timestamp.
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_stock_quotes_symbol_timestamp
+    ON stock_quotes (symbol, timestamp);
@@ -0,0 +1,3 @@
+-- Undo 003: drop the uniqueness target for the insert conflict policies.
+
+DROP INDEX IF EXISTS uniq_stock_quotes_symbol_timestamp;
@@ -0,0 +1,15 @@
+-- Fire a NOTIFY on every stock_quotes insert so the API service can push
+-- persisted quotes to live stream clients without polling (and without
+-- Redis). The payload is the inserted row as JSON.
+
+CREATE OR REPLACE FUNCTION notify_stock_quote_insert() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('quotron_quotes', row_to_json(NEW)::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS stock_quotes_notify ON stock_quotes;
+CREATE TRIGGER stock_quotes_notify
+    AFTER INSERT ON stock_quotes
+    FOR EACH ROW EXECUTE FUNCTION notify_stock_quote_insert();
@@ -0,0 +1,4 @@
+-- Undo 004: drop the insert trigger and its notify function.
+
+DROP TRIGGER IF EXISTS stock_quotes_notify ON stock_quotes;
+DROP FUNCTION IF EXISTS notify_stock_quote_insert();
@@ -0,0 +1,3 @@
+-- Indices and some instruments report no volume at all. Let the column
+-- hold NULL so "unknown volume" is distinguishable from a real zero.
+ALTER TABLE stock_quotes ALTER COLUMN volume DROP NOT NULL;
@@ -0,0 +1,4 @@
+-- Unknown volumes collapse back to zero; that loss is inherent to
+-- restoring the NOT NULL constraint.
+UPDATE stock_quotes SET volume = 0 WHERE volume IS NULL;
+ALTER TABLE stock_quotes ALTER COLUMN volume SET NOT NULL;
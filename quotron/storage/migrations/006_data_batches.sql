@@ -0,0 +1,12 @@
+-- Bookkeeping for ingested batches: one row per batch ID, updated as it
+-- moves through processing -> completed/failed/partial, so submitters
+-- can poll the outcome of a load instead of fire-and-forget.
+CREATE TABLE IF NOT EXISTS data_batches (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    quote_count INTEGER NOT NULL DEFAULT 0,
+    index_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+-- Crypto sources report rolling 24-hour extremes alongside the price.
+-- Nullable, because equity feeds do not carry them.
+ALTER TABLE stock_quotes ADD COLUMN high_24h DOUBLE PRECISION;
+ALTER TABLE stock_quotes ADD COLUMN low_24h DOUBLE PRECISION;
@@ -0,0 +1,2 @@
+ALTER TABLE stock_quotes DROP COLUMN high_24h;
+ALTER TABLE stock_quotes DROP COLUMN low_24h;
@@ -0,0 +1,7 @@
+-- /api/symbols enumerates coverage: distinct symbols with their quote
+-- count and latest timestamp, optionally filtered to one exchange. The
+-- (symbol, timestamp DESC) index from 002 serves the unfiltered group
+-- scan; this one serves the exchange-filtered variant.
+
+CREATE INDEX IF NOT EXISTS idx_stock_quotes_exchange_symbol
+    ON stock_quotes (exchange, symbol, timestamp DESC);
@@ -0,0 +1,3 @@
+-- Undo 008: drop the exchange-filtered coverage index.
+
+DROP INDEX IF EXISTS idx_stock_quotes_exchange_symbol;
@@ -0,0 +1,14 @@
+-- /health/history serves each data source's status timeline and uptime.
+-- Rows are appended only when a source's status changes, so the table
+-- grows with incidents, not with traffic; the API service prunes rows
+-- past its configured retention.
+
+CREATE TABLE IF NOT EXISTS health_history (
+    id BIGSERIAL PRIMARY KEY,
+    source TEXT NOT NULL,
+    status TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_health_history_source_changed
+    ON health_history (source, changed_at DESC);
@@ -0,0 +1,4 @@
+-- Undo 009: drop the health status timeline.
+
+DROP INDEX IF EXISTS idx_health_history_source_changed;
+DROP TABLE IF EXISTS health_history;
@@ -0,0 +1,36 @@
+-- OPTIONAL: convert stock_quotes to monthly range partitioning.
+-- Not applied by the default migration runner (it only reads the parent
+-- directory); apply by hand on deployments large enough to need it, then
+-- use ingest.EnsureFuturePartitions to keep partitions ahead of now.
+
+BEGIN;
+
+ALTER TABLE stock_quotes RENAME TO stock_quotes_unpartitioned;
+
+CREATE TABLE stock_quotes (
+    id BIGSERIAL,
+    symbol TEXT NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    change DOUBLE PRECISION NOT NULL,
+    change_percent DOUBLE PRECISION NOT NULL,
+    volume BIGINT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    exchange TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- Catch-all so inserts never fail while future partitions lag behind.
+CREATE TABLE stock_quotes_default PARTITION OF stock_quotes DEFAULT;
+
+CREATE INDEX IF NOT EXISTS idx_stock_quotes_symbol_timestamp
+    ON stock_quotes (symbol, timestamp DESC);
+
+INSERT INTO stock_quotes (symbol, price, change, change_percent, volume, timestamp, exchange, source, created_at)
+SELECT symbol, price, change, change_percent, volume, timestamp, exchange, source, created_at
+FROM stock_quotes_unpartitioned;
+
+DROP TABLE stock_quotes_unpartitioned;
+
+COMMIT;
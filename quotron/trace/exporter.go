@@ -0,0 +1,84 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Exporter receives finished spans. Implementations must be safe for
+// concurrent use; End calls ExportSpan from request goroutines.
+type Exporter interface {
+	ExportSpan(s *Span)
+}
+
+var exporterMu sync.RWMutex
+var exporter Exporter
+
+// SetExporter installs the process-wide span exporter. A nil exporter
+// (the default) drops spans, so tracing is free when not configured.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	exporter = e
+}
+
+func export(s *Span) {
+	exporterMu.RLock()
+	e := exporter
+	exporterMu.RUnlock()
+	if e != nil {
+		e.ExportSpan(s)
+	}
+}
+
+// HTTPExporter posts spans as JSON to a collector endpoint. Delivery is
+// best-effort and off the request path: spans queue into a buffer that a
+// background goroutine drains, and new spans are dropped (with a warning)
+// when the buffer is full rather than blocking a handler.
+type HTTPExporter struct {
+	endpoint string
+	client   *http.Client
+	spans    chan *Span
+	dropWarn sync.Once
+}
+
+// NewHTTPExporter starts an exporter posting spans to endpoint.
+func NewHTTPExporter(endpoint string) *HTTPExporter {
+	e := &HTTPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		spans:    make(chan *Span, 256),
+	}
+	go e.drain()
+	return e
+}
+
+// ExportSpan queues a span for delivery, dropping it if the buffer is full.
+func (e *HTTPExporter) ExportSpan(s *Span) {
+	select {
+	case e.spans <- s:
+	default:
+		e.dropWarn.Do(func() {
+			log.Printf("WARNING: trace export buffer full, dropping spans (collector at %s too slow?)", e.endpoint)
+		})
+	}
+}
+
+func (e *HTTPExporter) drain() {
+	for s := range e.spans {
+		body, err := json.Marshal(s)
+		if err != nil {
+			continue
+		}
+		resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("WARNING: exporting span %s: %v", s.Name, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// traceparentHeader is the W3C trace-context header (and the field name
+// used in Redis stream messages).
+const traceparentHeader = "traceparent"
+
+// Traceparent renders the active span context in W3C form
+// (00-traceid-spanid-01), or "" when ctx carries no span. The string
+// also serves as the traceparent field on Redis stream messages.
+func Traceparent(ctx context.Context) string {
+	sc, ok := ctx.Value(ctxKey{}).(spanContext)
+	if !ok {
+		return ""
+	}
+	return "00-" + sc.TraceID + "-" + sc.SpanID + "-01"
+}
+
+// WithRemote resumes a trace from a serialized traceparent, so spans
+// started from the returned context join the caller's trace. Malformed
+// values are ignored and ctx is returned unchanged.
+func WithRemote(ctx context.Context, traceparent string) context.Context {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || parts[0] != "00" || !isHex(parts[1], 32) || !isHex(parts[2], 16) {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, spanContext{TraceID: parts[1], SpanID: parts[2]})
+}
+
+// Inject writes the active span context into outgoing HTTP headers.
+func Inject(ctx context.Context, h http.Header) {
+	if tp := Traceparent(ctx); tp != "" {
+		h.Set(traceparentHeader, tp)
+	}
+}
+
+// Extract resumes the trace carried by incoming HTTP headers, if any.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	return WithRemote(ctx, h.Get(traceparentHeader))
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,80 @@
+// Package trace is a minimal tracing layer: named spans with attributes,
+// W3C trace-context propagation, and a pluggable exporter. We deliberately
+// avoid the OpenTelemetry SDK: the services need spans, traceparent
+// propagation, and one exporter, and nothing else — the wire formats are
+// compatible, so spans still line up in an OTLP-speaking backend.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// spanContext identifies the active span in a context, whether it was
+// started locally or resumed from a propagated traceparent.
+type spanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+type ctxKey struct{}
+
+// Span is one timed operation within a trace. Callers set attributes
+// between StartSpan and End; End hands the span to the exporter.
+type Span struct {
+	Name     string            `json:"name"`
+	TraceID  string            `json:"traceId"`
+	SpanID   string            `json:"spanId"`
+	ParentID string            `json:"parentSpanId,omitempty"`
+	Start    time.Time         `json:"start"`
+	EndTime  time.Time         `json:"end"`
+	Attrs    map[string]string `json:"attributes,omitempty"`
+}
+
+// StartSpan begins a span as a child of whatever span is in ctx (local
+// or propagated), returning a context carrying the new span.
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{
+		Name:    name,
+		TraceID: newID(16),
+		SpanID:  newID(8),
+		Start:   time.Now(),
+	}
+	if parent, ok := ctx.Value(ctxKey{}).(spanContext); ok {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	}
+	ctx = context.WithValue(ctx, ctxKey{}, spanContext{TraceID: span.TraceID, SpanID: span.SpanID})
+	return ctx, span
+}
+
+// SetAttr records a key/value attribute on the span.
+func (s *Span) SetAttr(key, value string) {
+	if s.Attrs == nil {
+		s.Attrs = map[string]string{}
+	}
+	s.Attrs[key] = value
+}
+
+// RecordError marks the span as failed with the error's message.
+// A nil error is a no-op, so it can wrap a return value directly.
+func (s *Span) RecordError(err error) {
+	if err != nil {
+		s.SetAttr("error", err.Error())
+	}
+}
+
+// End stamps the span's end time and hands it to the exporter.
+func (s *Span) End() {
+	s.EndTime = time.Now()
+	export(s)
+}
+
+// newID returns n random bytes hex-encoded, the W3C trace/span ID shape.
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,98 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingExporter collects finished spans for assertions.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *recordingExporter) ExportSpan(s *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+func (e *recordingExporter) all() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Span(nil), e.spans...)
+}
+
+func TestChildSpanSharesTrace(t *testing.T) {
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartSpan(ctx, "child")
+
+	if child.TraceID != parent.TraceID {
+		t.Errorf("child trace = %s, want parent's %s", child.TraceID, parent.TraceID)
+	}
+	if child.ParentID != parent.SpanID {
+		t.Errorf("child parent = %s, want %s", child.ParentID, parent.SpanID)
+	}
+	if child.SpanID == parent.SpanID {
+		t.Error("child should get its own span ID")
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "origin")
+	tp := Traceparent(ctx)
+	if !strings.HasPrefix(tp, "00-"+span.TraceID+"-"+span.SpanID) {
+		t.Fatalf("traceparent = %q, want it to carry %s/%s", tp, span.TraceID, span.SpanID)
+	}
+
+	_, resumed := StartSpan(WithRemote(context.Background(), tp), "remote-child")
+	if resumed.TraceID != span.TraceID {
+		t.Errorf("resumed trace = %s, want %s", resumed.TraceID, span.TraceID)
+	}
+	if resumed.ParentID != span.SpanID {
+		t.Errorf("resumed parent = %s, want %s", resumed.ParentID, span.SpanID)
+	}
+}
+
+func TestWithRemoteIgnoresMalformed(t *testing.T) {
+	for _, tp := range []string{"", "garbage", "00-short-span-01", "01-" + strings.Repeat("a", 32) + "-" + strings.Repeat("b", 16) + "-01"} {
+		ctx := WithRemote(context.Background(), tp)
+		if Traceparent(ctx) != "" {
+			t.Errorf("WithRemote(%q) should not install a span context", tp)
+		}
+	}
+}
+
+func TestHeaderPropagation(t *testing.T) {
+	ctx, _ := StartSpan(context.Background(), "client-side")
+	h := http.Header{}
+	Inject(ctx, h)
+
+	if Traceparent(Extract(context.Background(), h)) != Traceparent(ctx) {
+		t.Error("Extract(Inject(ctx)) should restore the span context")
+	}
+}
+
+func TestEndExportsSpan(t *testing.T) {
+	rec := &recordingExporter{}
+	SetExporter(rec)
+	defer SetExporter(nil)
+
+	_, span := StartSpan(context.Background(), "op")
+	span.SetAttr("symbol", "AAPL")
+	span.End()
+
+	spans := rec.all()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != "op" || spans[0].Attrs["symbol"] != "AAPL" {
+		t.Errorf("exported span %+v lost its name or attributes", spans[0])
+	}
+	if spans[0].EndTime.Before(spans[0].Start) {
+		t.Error("span end should not precede its start")
+	}
+}